@@ -1,33 +1,398 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Default values of the configuration
+const (
+	DefaultCommandPrefix  = "!"
+	DefaultDatabaseDriver = "sqlite"
+	DefaultDatabaseURL    = "data.db"
+	DefaultLogLevel       = "info"
+	DefaultLogFormat      = "text"
+	DefaultEmbedColor     = 0x20B020
+
+	DefaultMaximumNoteLength               = 240
+	DefaultMaximumNumberOfRemindersPerUser = 35
+	DefaultMinimumReminderDuration         = time.Minute
+	DefaultMaximumReminderDuration         = 1825 * 24 * time.Hour
+	DefaultReactionReminderDuration        = 8 * time.Hour
+	DefaultReminderListPageSize            = 7
+
+	DefaultMaximumConfigurableRemindersPerUser = 100
+	DefaultMinimumRepeatInterval               = time.Hour
+	DefaultSchedulerCapacity                   = 1000
+	DefaultDeliveredReminderRetention          = 7 * 24 * time.Hour
+	DefaultMaximumDeliveryAttempts             = 8
+	DefaultDeliveryRetryBaseDelay              = time.Minute
+)
+
+// DefaultReminderEmojis are the emojis that users can react to a message with to be reminded about it, by default
+var DefaultReminderEmojis = []string{"⏰", "⏲️", "🎗️️"}
+
+const (
+	// MaximumNoteLength is the highest maximum length of notes that can be configured, which keeps notification
+	// messages within the limits of Discord's embeds
+	MaximumNoteLength = 2000
+
+	// MaximumReminderListPageSize is the highest number of reminders per page of the list of reminders that can be
+	// configured
+	MaximumReminderListPageSize = 25
+
+	// MaximumDeliveryAttempts and MaximumDeliveryRetryBaseDelay are the highest maximum number of delivery attempts and
+	// base delay between them that can be configured, which keep the delay before the last attempt from overflowing
+	MaximumDeliveryAttempts       = 16
+	MaximumDeliveryRetryBaseDelay = 24 * time.Hour
 )
 
 var cfg *Config
 
+// Config is the configuration of the bot.
+// It is read from the YAML file whose path is set by the CONFIG_PATH environment variable, if any, and every value
+// can be overridden by an environment variable.
 type Config struct {
-	DiscordToken  string
-	CommandPrefix string
+	DiscordToken  string `yaml:"discord-bot-token"`
+	CommandPrefix string `yaml:"command-prefix"`
+
+	// DeliveryFallbackToOriginChannel is whether reminders that can't be sent by direct message should be delivered
+	// in the channel they were created from instead
+	DeliveryFallbackToOriginChannel bool `yaml:"delivery-fallback-to-origin-channel"`
+
+	// DatabaseDriver is the driver of the database in which reminders are persisted (sqlite or postgres)
+	DatabaseDriver string `yaml:"database-driver"`
+
+	// DatabaseURL is the path of the database file for SQLite, or the connection URL for PostgreSQL
+	DatabaseURL string `yaml:"database-url"`
+
+	// MetricsAddress is the address on which Prometheus metrics are exposed (e.g. :9090), or empty if metrics are disabled
+	MetricsAddress string `yaml:"metrics-address"`
+
+	// HealthAddress is the address on which the /healthz and /readyz endpoints are exposed (e.g. :8080), or empty if
+	// they are disabled. It may be the same as MetricsAddress.
+	HealthAddress string `yaml:"health-address"`
+
+	// LogLevel is the minimum level of the logs written (debug, info, warn or error)
+	LogLevel string `yaml:"log-level"`
+
+	// LogFormat is the format of the logs written (text or json)
+	LogFormat string `yaml:"log-format"`
+
+	// LogNotes is whether the notes of reminders and the arguments of commands are logged rather than redacted
+	LogNotes bool `yaml:"log-notes"`
+
+	// ReminderEmojis are the emojis that users can react to a message with to be reminded about it
+	ReminderEmojis []string `yaml:"reminder-emojis"`
+
+	// EmbedColor is the color of the embeds sent by the bot (e.g. 0x20B020)
+	EmbedColor int `yaml:"embed-color"`
+
+	// Limits are the limits and defaults applied to reminders
+	Limits Limits `yaml:"limits"`
 }
 
-func load() {
-	cfg = &Config{
-		DiscordToken:  strings.TrimSpace(os.Getenv("DISCORD_BOT_TOKEN")),
-		CommandPrefix: strings.TrimSpace(os.Getenv("COMMAND_PREFIX")),
+// Limits are the limits and defaults applied to reminders
+type Limits struct {
+	// MaximumNoteLength is the maximum number of characters of the note of a reminder
+	MaximumNoteLength int `yaml:"maximum-note-length"`
+
+	// MaximumNumberOfRemindersPerUser is the maximum number of reminders a user can have, unless the guild the
+	// reminder is created from configured another maximum
+	MaximumNumberOfRemindersPerUser int `yaml:"maximum-reminders-per-user"`
+
+	// MinimumReminderDuration and MaximumReminderDuration are the bounds of in how long reminders can be due
+	MinimumReminderDuration time.Duration `yaml:"minimum-reminder-duration"`
+	MaximumReminderDuration time.Duration `yaml:"maximum-reminder-duration"`
+
+	// DefaultReactionReminderDuration is in how long reminders created by reacting to a message are due, unless the
+	// guild the reaction comes from configured another duration
+	DefaultReactionReminderDuration time.Duration `yaml:"default-reaction-reminder-duration"`
+
+	// ReminderListPageSize is the number of reminders per page of the list of reminders
+	ReminderListPageSize int `yaml:"reminder-list-page-size"`
+
+	// MaximumConfigurableRemindersPerUser is the highest maximum number of reminders per user a guild can configure
+	MaximumConfigurableRemindersPerUser int `yaml:"maximum-configurable-reminders-per-user"`
+
+	// MinimumRepeatInterval is the shortest interval at which a reminder can repeat
+	MinimumRepeatInterval time.Duration `yaml:"minimum-repeat-interval"`
+
+	// SchedulerCapacity is the maximum number of reminders kept in memory by the scheduler
+	SchedulerCapacity int `yaml:"scheduler-capacity"`
+
+	// DeliveredReminderRetention is for how long a reminder can be snoozed after it has been delivered
+	DeliveredReminderRetention time.Duration `yaml:"delivered-reminder-retention"`
+
+	// MaximumDeliveryAttempts is the number of times the delivery of a reminder is attempted before giving up on it
+	MaximumDeliveryAttempts int `yaml:"maximum-delivery-attempts"`
+
+	// DeliveryRetryBaseDelay is how long to wait before retrying the delivery of a reminder for the first time.
+	// The delay doubles with every failed attempt.
+	DeliveryRetryBaseDelay time.Duration `yaml:"delivery-retry-base-delay"`
+}
+
+// Default returns the default configuration, which only lacks a Discord bot token.
+// DatabaseURL is left empty, since it only has a default for SQLite, which is set when the configuration is loaded.
+func Default() *Config {
+	return &Config{
+		CommandPrefix:  DefaultCommandPrefix,
+		DatabaseDriver: DefaultDatabaseDriver,
+		LogLevel:       DefaultLogLevel,
+		LogFormat:      DefaultLogFormat,
+		ReminderEmojis: append([]string(nil), DefaultReminderEmojis...),
+		EmbedColor:     DefaultEmbedColor,
+		Limits: Limits{
+			MaximumNoteLength:               DefaultMaximumNoteLength,
+			MaximumNumberOfRemindersPerUser: DefaultMaximumNumberOfRemindersPerUser,
+			MinimumReminderDuration:         DefaultMinimumReminderDuration,
+			MaximumReminderDuration:         DefaultMaximumReminderDuration,
+			DefaultReactionReminderDuration: DefaultReactionReminderDuration,
+			ReminderListPageSize:            DefaultReminderListPageSize,
+
+			MaximumConfigurableRemindersPerUser: DefaultMaximumConfigurableRemindersPerUser,
+			MinimumRepeatInterval:               DefaultMinimumRepeatInterval,
+			SchedulerCapacity:                   DefaultSchedulerCapacity,
+			DeliveredReminderRetention:          DefaultDeliveredReminderRetention,
+			MaximumDeliveryAttempts:             DefaultMaximumDeliveryAttempts,
+			DeliveryRetryBaseDelay:              DefaultDeliveryRetryBaseDelay,
+		},
 	}
-	if len(cfg.DiscordToken) == 0 {
-		panic("environment variable 'DISCORD_BOT_TOKEN' must not be empty")
+}
+
+// Load reads the configuration from the file whose path is set by the CONFIG_PATH environment variable, if any,
+// overrides it with the environment variables that are set and validates it
+func Load() (*Config, error) {
+	config := Default()
+	if path := strings.TrimSpace(os.Getenv("CONFIG_PATH")); len(path) > 0 {
+		if err := config.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := config.readEnvironment(); err != nil {
+		return nil, err
 	}
-	if len(cfg.CommandPrefix) == 0 {
-		cfg.CommandPrefix = "!"
+	config.normalize()
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
+	cfg = config
+	return cfg, nil
 }
 
+// Get returns the configuration, loading it if it hasn't been loaded yet
 func Get() *Config {
 	if cfg == nil {
-		load()
+		if _, err := Load(); err != nil {
+			panic(err)
+		}
 	}
 	return cfg
 }
+
+// Validate returns an error describing every invalid value of the configuration, if any
+func (config *Config) Validate() error {
+	var errs []error
+	if len(config.DiscordToken) == 0 {
+		errs = append(errs, errors.New("discord-bot-token (DISCORD_BOT_TOKEN) must not be empty"))
+	}
+	if len(config.CommandPrefix) == 0 {
+		errs = append(errs, errors.New("command-prefix (COMMAND_PREFIX) must not be empty"))
+	}
+	if config.DatabaseDriver != "sqlite" && config.DatabaseDriver != "postgres" {
+		errs = append(errs, fmt.Errorf("database-driver (DATABASE_DRIVER) must be sqlite or postgres, got '%s'", config.DatabaseDriver))
+	}
+	if len(config.DatabaseURL) == 0 {
+		errs = append(errs, errors.New("database-url (DATABASE_URL) must not be empty when database-driver is not sqlite"))
+	}
+	switch config.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log-level (LOG_LEVEL) must be debug, info, warn or error, got '%s'", config.LogLevel))
+	}
+	if config.LogFormat != "text" && config.LogFormat != "json" {
+		errs = append(errs, fmt.Errorf("log-format (LOG_FORMAT) must be text or json, got '%s'", config.LogFormat))
+	}
+	if len(config.ReminderEmojis) == 0 {
+		errs = append(errs, errors.New("reminder-emojis (REMINDER_EMOJIS) must contain at least one emoji"))
+	}
+	for _, emoji := range config.ReminderEmojis {
+		if len(emoji) == 0 {
+			errs = append(errs, errors.New("reminder-emojis (REMINDER_EMOJIS) must not contain empty emojis"))
+			break
+		}
+	}
+	if config.EmbedColor < 0 || config.EmbedColor > 0xFFFFFF {
+		errs = append(errs, fmt.Errorf("embed-color (EMBED_COLOR) must be between 0x000000 and 0xFFFFFF, got %#x", config.EmbedColor))
+	}
+	limits := config.Limits
+	if limits.MaximumNoteLength < 1 || limits.MaximumNoteLength > MaximumNoteLength {
+		errs = append(errs, fmt.Errorf("limits.maximum-note-length (MAXIMUM_NOTE_LENGTH) must be between 1 and %d, got %d", MaximumNoteLength, limits.MaximumNoteLength))
+	}
+	if limits.MaximumNumberOfRemindersPerUser < 1 {
+		errs = append(errs, fmt.Errorf("limits.maximum-reminders-per-user (MAXIMUM_REMINDERS_PER_USER) must be at least 1, got %d", limits.MaximumNumberOfRemindersPerUser))
+	}
+	if limits.MinimumReminderDuration < time.Second {
+		errs = append(errs, fmt.Errorf("limits.minimum-reminder-duration (MINIMUM_REMINDER_DURATION) must be at least 1s, got %s", limits.MinimumReminderDuration))
+	}
+	if limits.MaximumReminderDuration <= limits.MinimumReminderDuration {
+		errs = append(errs, fmt.Errorf("limits.maximum-reminder-duration (MAXIMUM_REMINDER_DURATION) must be greater than limits.minimum-reminder-duration (%s), got %s", limits.MinimumReminderDuration, limits.MaximumReminderDuration))
+	}
+	if limits.DefaultReactionReminderDuration < limits.MinimumReminderDuration || limits.DefaultReactionReminderDuration > limits.MaximumReminderDuration {
+		errs = append(errs, fmt.Errorf("limits.default-reaction-reminder-duration (DEFAULT_REACTION_REMINDER_DURATION) must be between %s and %s, got %s", limits.MinimumReminderDuration, limits.MaximumReminderDuration, limits.DefaultReactionReminderDuration))
+	}
+	if limits.ReminderListPageSize < 1 || limits.ReminderListPageSize > MaximumReminderListPageSize {
+		errs = append(errs, fmt.Errorf("limits.reminder-list-page-size (REMINDER_LIST_PAGE_SIZE) must be between 1 and %d, got %d", MaximumReminderListPageSize, limits.ReminderListPageSize))
+	}
+	if limits.MaximumConfigurableRemindersPerUser < limits.MaximumNumberOfRemindersPerUser {
+		errs = append(errs, fmt.Errorf("limits.maximum-configurable-reminders-per-user (MAXIMUM_CONFIGURABLE_REMINDERS_PER_USER) must be at least limits.maximum-reminders-per-user (%d), got %d", limits.MaximumNumberOfRemindersPerUser, limits.MaximumConfigurableRemindersPerUser))
+	}
+	if limits.MinimumRepeatInterval < limits.MinimumReminderDuration || limits.MinimumRepeatInterval > limits.MaximumReminderDuration {
+		errs = append(errs, fmt.Errorf("limits.minimum-repeat-interval (MINIMUM_REPEAT_INTERVAL) must be between %s and %s, got %s", limits.MinimumReminderDuration, limits.MaximumReminderDuration, limits.MinimumRepeatInterval))
+	}
+	if limits.SchedulerCapacity < 1 {
+		errs = append(errs, fmt.Errorf("limits.scheduler-capacity (SCHEDULER_CAPACITY) must be at least 1, got %d", limits.SchedulerCapacity))
+	}
+	if limits.DeliveredReminderRetention < time.Minute {
+		errs = append(errs, fmt.Errorf("limits.delivered-reminder-retention (DELIVERED_REMINDER_RETENTION) must be at least 1m, got %s", limits.DeliveredReminderRetention))
+	}
+	if limits.MaximumDeliveryAttempts < 1 || limits.MaximumDeliveryAttempts > MaximumDeliveryAttempts {
+		errs = append(errs, fmt.Errorf("limits.maximum-delivery-attempts (MAXIMUM_DELIVERY_ATTEMPTS) must be between 1 and %d, got %d", MaximumDeliveryAttempts, limits.MaximumDeliveryAttempts))
+	}
+	if limits.DeliveryRetryBaseDelay < time.Second || limits.DeliveryRetryBaseDelay > MaximumDeliveryRetryBaseDelay {
+		errs = append(errs, fmt.Errorf("limits.delivery-retry-base-delay (DELIVERY_RETRY_BASE_DELAY) must be between 1s and %s, got %s", MaximumDeliveryRetryBaseDelay, limits.DeliveryRetryBaseDelay))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// readFile overrides the configuration with the values set in the YAML file at path
+func (config *Config) readFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open configuration file: %w", err)
+	}
+	defer file.Close()
+	decoder := yaml.NewDecoder(file)
+	// Reject unknown keys, so that typos don't silently leave the default value in place
+	decoder.KnownFields(true)
+	if err = decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse configuration file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// readEnvironment overrides the configuration with the values of the environment variables that are set
+func (config *Config) readEnvironment() error {
+	var errs []error
+	readString(&config.DiscordToken, "DISCORD_BOT_TOKEN")
+	readString(&config.CommandPrefix, "COMMAND_PREFIX")
+	errs = append(errs, readBool(&config.DeliveryFallbackToOriginChannel, "DELIVERY_FALLBACK_TO_ORIGIN_CHANNEL"))
+	readString(&config.DatabaseDriver, "DATABASE_DRIVER")
+	readString(&config.DatabaseURL, "DATABASE_URL")
+	readString(&config.MetricsAddress, "METRICS_ADDRESS")
+	readString(&config.HealthAddress, "HEALTH_ADDRESS")
+	readString(&config.LogLevel, "LOG_LEVEL")
+	readString(&config.LogFormat, "LOG_FORMAT")
+	errs = append(errs, readBool(&config.LogNotes, "LOG_NOTES"))
+	if value := strings.TrimSpace(os.Getenv("REMINDER_EMOJIS")); len(value) > 0 {
+		config.ReminderEmojis = strings.Split(value, ",")
+	}
+	if value := strings.TrimSpace(os.Getenv("EMBED_COLOR")); len(value) > 0 {
+		color, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(value), "#"), "0x"), 16, 32)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("environment variable 'EMBED_COLOR' must be a hexadecimal color (e.g. 0x20B020), got '%s'", value))
+		} else {
+			config.EmbedColor = int(color)
+		}
+	}
+	errs = append(errs, readInt(&config.Limits.MaximumNoteLength, "MAXIMUM_NOTE_LENGTH"))
+	errs = append(errs, readInt(&config.Limits.MaximumNumberOfRemindersPerUser, "MAXIMUM_REMINDERS_PER_USER"))
+	errs = append(errs, readDuration(&config.Limits.MinimumReminderDuration, "MINIMUM_REMINDER_DURATION"))
+	errs = append(errs, readDuration(&config.Limits.MaximumReminderDuration, "MAXIMUM_REMINDER_DURATION"))
+	errs = append(errs, readDuration(&config.Limits.DefaultReactionReminderDuration, "DEFAULT_REACTION_REMINDER_DURATION"))
+	errs = append(errs, readInt(&config.Limits.ReminderListPageSize, "REMINDER_LIST_PAGE_SIZE"))
+	errs = append(errs, readInt(&config.Limits.MaximumConfigurableRemindersPerUser, "MAXIMUM_CONFIGURABLE_REMINDERS_PER_USER"))
+	errs = append(errs, readDuration(&config.Limits.MinimumRepeatInterval, "MINIMUM_REPEAT_INTERVAL"))
+	errs = append(errs, readInt(&config.Limits.SchedulerCapacity, "SCHEDULER_CAPACITY"))
+	errs = append(errs, readDuration(&config.Limits.DeliveredReminderRetention, "DELIVERED_REMINDER_RETENTION"))
+	errs = append(errs, readInt(&config.Limits.MaximumDeliveryAttempts, "MAXIMUM_DELIVERY_ATTEMPTS"))
+	errs = append(errs, readDuration(&config.Limits.DeliveryRetryBaseDelay, "DELIVERY_RETRY_BASE_DELAY"))
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid environment: %w", err)
+	}
+	return nil
+}
+
+// normalize trims the values of the configuration, lowercases the ones that are case-insensitive and sets the path of
+// the database file if SQLite is used and no path was set
+func (config *Config) normalize() {
+	config.DiscordToken = strings.TrimSpace(config.DiscordToken)
+	config.CommandPrefix = strings.TrimSpace(config.CommandPrefix)
+	config.DatabaseDriver = strings.ToLower(strings.TrimSpace(config.DatabaseDriver))
+	config.DatabaseURL = strings.TrimSpace(config.DatabaseURL)
+	if len(config.DatabaseURL) == 0 && config.DatabaseDriver == DefaultDatabaseDriver {
+		config.DatabaseURL = DefaultDatabaseURL
+	}
+	config.MetricsAddress = strings.TrimSpace(config.MetricsAddress)
+	config.HealthAddress = strings.TrimSpace(config.HealthAddress)
+	config.LogLevel = strings.ToLower(strings.TrimSpace(config.LogLevel))
+	config.LogFormat = strings.ToLower(strings.TrimSpace(config.LogFormat))
+	for i, emoji := range config.ReminderEmojis {
+		config.ReminderEmojis[i] = strings.TrimSpace(emoji)
+	}
+}
+
+func readString(value *string, name string) {
+	if v := strings.TrimSpace(os.Getenv(name)); len(v) > 0 {
+		*value = v
+	}
+}
+
+func readBool(value *bool, name string) error {
+	v := strings.TrimSpace(os.Getenv(name))
+	if len(v) == 0 {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("environment variable '%s' must be true or false, got '%s'", name, v)
+	}
+	*value = parsed
+	return nil
+}
+
+func readInt(value *int, name string) error {
+	v := strings.TrimSpace(os.Getenv(name))
+	if len(v) == 0 {
+		return nil
+	}
+	parsed, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("environment variable '%s' must be an integer, got '%s'", name, v)
+	}
+	*value = parsed
+	return nil
+}
+
+func readDuration(value *time.Duration, name string) error {
+	v := strings.TrimSpace(os.Getenv(name))
+	if len(v) == 0 {
+		return nil
+	}
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("environment variable '%s' must be a duration (e.g. 8h or 30m), got '%s'", name, v)
+	}
+	*value = parsed
+	return nil
+}
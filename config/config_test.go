@@ -0,0 +1,193 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	scenarios := []struct {
+		Name          string
+		File          string
+		Environment   map[string]string
+		ExpectedError string
+		Check         func(t *testing.T, cfg *Config)
+	}{
+		{
+			Name:        "defaults",
+			Environment: map[string]string{"DISCORD_BOT_TOKEN": "token"},
+			Check: func(t *testing.T, cfg *Config) {
+				if cfg.CommandPrefix != DefaultCommandPrefix || cfg.DatabaseDriver != DefaultDatabaseDriver || cfg.DatabaseURL != DefaultDatabaseURL {
+					t.Error("expected default command prefix and database, got", cfg.CommandPrefix, cfg.DatabaseDriver, cfg.DatabaseURL)
+				}
+				if cfg.Limits.MaximumNoteLength != DefaultMaximumNoteLength || cfg.Limits.DefaultReactionReminderDuration != DefaultReactionReminderDuration {
+					t.Error("expected default limits, got", cfg.Limits)
+				}
+				if cfg.EmbedColor != DefaultEmbedColor || len(cfg.ReminderEmojis) != len(DefaultReminderEmojis) {
+					t.Error("expected default embed color and emojis, got", cfg.EmbedColor, cfg.ReminderEmojis)
+				}
+			},
+		},
+		{
+			Name: "file",
+			File: `
+discord-bot-token: token
+command-prefix: "?"
+database-url: reminders.db
+reminder-emojis: ["👀"]
+embed-color: 0xFF0000
+limits:
+  maximum-note-length: 500
+  maximum-reminders-per-user: 100
+  minimum-reminder-duration: 5m
+  maximum-reminder-duration: 720h
+  default-reaction-reminder-duration: 24h
+  reminder-list-page-size: 10
+  maximum-configurable-reminders-per-user: 200
+  minimum-repeat-interval: 30m
+  scheduler-capacity: 500
+  delivered-reminder-retention: 24h
+  maximum-delivery-attempts: 3
+  delivery-retry-base-delay: 30s
+`,
+			Check: func(t *testing.T, cfg *Config) {
+				if cfg.DiscordToken != "token" || cfg.CommandPrefix != "?" || cfg.DatabaseURL != "reminders.db" {
+					t.Error("expected values of the file, got", cfg.DiscordToken, cfg.CommandPrefix, cfg.DatabaseURL)
+				}
+				if len(cfg.ReminderEmojis) != 1 || cfg.ReminderEmojis[0] != "👀" || cfg.EmbedColor != 0xFF0000 {
+					t.Error("expected emojis and embed color of the file, got", cfg.ReminderEmojis, cfg.EmbedColor)
+				}
+				expectedLimits := Limits{
+					MaximumNoteLength:               500,
+					MaximumNumberOfRemindersPerUser: 100,
+					MinimumReminderDuration:         5 * time.Minute,
+					MaximumReminderDuration:         720 * time.Hour,
+					DefaultReactionReminderDuration: 24 * time.Hour,
+					ReminderListPageSize:            10,
+
+					MaximumConfigurableRemindersPerUser: 200,
+					MinimumRepeatInterval:               30 * time.Minute,
+					SchedulerCapacity:                   500,
+					DeliveredReminderRetention:          24 * time.Hour,
+					MaximumDeliveryAttempts:             3,
+					DeliveryRetryBaseDelay:              30 * time.Second,
+				}
+				if cfg.Limits != expectedLimits {
+					t.Errorf("expected limits %+v, got %+v", expectedLimits, cfg.Limits)
+				}
+			},
+		},
+		{
+			Name: "environment-overrides-file",
+			File: `
+discord-bot-token: token
+limits:
+  maximum-note-length: 500
+`,
+			Environment: map[string]string{
+				"MAXIMUM_NOTE_LENGTH": "100",
+				"REMINDER_EMOJIS":     "👀, 🔔",
+				"EMBED_COLOR":         "#0000FF",
+				"LOG_NOTES":           "true",
+			},
+			Check: func(t *testing.T, cfg *Config) {
+				if cfg.Limits.MaximumNoteLength != 100 {
+					t.Error("expected environment variable to override file, got", cfg.Limits.MaximumNoteLength)
+				}
+				if len(cfg.ReminderEmojis) != 2 || cfg.ReminderEmojis[1] != "🔔" || cfg.EmbedColor != 0x0000FF || !cfg.LogNotes {
+					t.Error("expected values of the environment, got", cfg.ReminderEmojis, cfg.EmbedColor, cfg.LogNotes)
+				}
+			},
+		},
+		{
+			Name:          "missing-token",
+			ExpectedError: "discord-bot-token (DISCORD_BOT_TOKEN) must not be empty",
+		},
+		{
+			Name:          "postgres-without-url",
+			Environment:   map[string]string{"DISCORD_BOT_TOKEN": "token", "DATABASE_DRIVER": "postgres"},
+			ExpectedError: "database-url (DATABASE_URL) must not be empty",
+		},
+		{
+			Name:          "unknown-key",
+			File:          "discord-bot-token: token\nmaximum-note-length: 500\n",
+			ExpectedError: "field maximum-note-length not found",
+		},
+		{
+			Name:          "invalid-environment-variable",
+			Environment:   map[string]string{"DISCORD_BOT_TOKEN": "token", "MINIMUM_REMINDER_DURATION": "soon"},
+			ExpectedError: "environment variable 'MINIMUM_REMINDER_DURATION' must be a duration",
+		},
+		{
+			Name:          "minimum-duration-above-maximum-duration",
+			Environment:   map[string]string{"DISCORD_BOT_TOKEN": "token", "MINIMUM_REMINDER_DURATION": "48h", "MAXIMUM_REMINDER_DURATION": "24h"},
+			ExpectedError: "limits.maximum-reminder-duration (MAXIMUM_REMINDER_DURATION) must be greater than",
+		},
+		{
+			Name:          "reaction-duration-out-of-bounds",
+			Environment:   map[string]string{"DISCORD_BOT_TOKEN": "token", "DEFAULT_REACTION_REMINDER_DURATION": "30s"},
+			ExpectedError: "limits.default-reaction-reminder-duration (DEFAULT_REACTION_REMINDER_DURATION) must be between",
+		},
+		{
+			Name:          "invalid-page-size",
+			Environment:   map[string]string{"DISCORD_BOT_TOKEN": "token", "REMINDER_LIST_PAGE_SIZE": "0"},
+			ExpectedError: "limits.reminder-list-page-size (REMINDER_LIST_PAGE_SIZE) must be between 1 and 25",
+		},
+		{
+			Name:          "configurable-reminders-below-default-maximum",
+			Environment:   map[string]string{"DISCORD_BOT_TOKEN": "token", "MAXIMUM_CONFIGURABLE_REMINDERS_PER_USER": "10"},
+			ExpectedError: "limits.maximum-configurable-reminders-per-user (MAXIMUM_CONFIGURABLE_REMINDERS_PER_USER) must be at least limits.maximum-reminders-per-user (35), got 10",
+		},
+		{
+			Name:          "repeat-interval-below-minimum-duration",
+			Environment:   map[string]string{"DISCORD_BOT_TOKEN": "token", "MINIMUM_REPEAT_INTERVAL": "30s"},
+			ExpectedError: "limits.minimum-repeat-interval (MINIMUM_REPEAT_INTERVAL) must be between",
+		},
+		{
+			Name:          "too-many-delivery-attempts",
+			Environment:   map[string]string{"DISCORD_BOT_TOKEN": "token", "MAXIMUM_DELIVERY_ATTEMPTS": "100"},
+			ExpectedError: "limits.maximum-delivery-attempts (MAXIMUM_DELIVERY_ATTEMPTS) must be between 1 and 16, got 100",
+		},
+		{
+			Name:          "invalid-scheduler-capacity",
+			Environment:   map[string]string{"DISCORD_BOT_TOKEN": "token", "SCHEDULER_CAPACITY": "0"},
+			ExpectedError: "limits.scheduler-capacity (SCHEDULER_CAPACITY) must be at least 1, got 0",
+		},
+		{
+			Name:          "multiple-errors",
+			Environment:   map[string]string{"LOG_LEVEL": "verbose", "EMBED_COLOR": "1000000"},
+			ExpectedError: "log-level (LOG_LEVEL) must be debug, info, warn or error, got 'verbose'",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			for _, name := range []string{"CONFIG_PATH", "DISCORD_BOT_TOKEN", "DATABASE_DRIVER", "DATABASE_URL", "LOG_LEVEL", "LOG_NOTES", "REMINDER_EMOJIS", "EMBED_COLOR", "MAXIMUM_NOTE_LENGTH", "MINIMUM_REMINDER_DURATION", "MAXIMUM_REMINDER_DURATION", "DEFAULT_REACTION_REMINDER_DURATION", "REMINDER_LIST_PAGE_SIZE", "MAXIMUM_CONFIGURABLE_REMINDERS_PER_USER", "MINIMUM_REPEAT_INTERVAL", "SCHEDULER_CAPACITY", "DELIVERED_REMINDER_RETENTION", "MAXIMUM_DELIVERY_ATTEMPTS", "DELIVERY_RETRY_BASE_DELAY"} {
+				t.Setenv(name, "")
+			}
+			if len(scenario.File) > 0 {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				if err := os.WriteFile(path, []byte(scenario.File), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("CONFIG_PATH", path)
+			}
+			for name, value := range scenario.Environment {
+				t.Setenv(name, value)
+			}
+			cfg, err := Load()
+			if len(scenario.ExpectedError) > 0 {
+				if err == nil || !strings.Contains(err.Error(), scenario.ExpectedError) {
+					t.Fatalf("expected error containing %q, got %v", scenario.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expected no error, got", err.Error())
+			}
+			scenario.Check(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package core
+
+import "time"
+
+// DeliveredReminder is a reminder that has already been delivered, kept so that the user can snooze it
+type DeliveredReminder struct {
+	MessageID   string    // ID of the message through which the reminder was delivered
+	UserID      string    // ID of the user the reminder was delivered to
+	ChannelID   string    // ID of the channel the reminder was delivered in, or empty if it was delivered by direct message
+	MessageLink string    // Link to the message that the user wanted to be reminded about
+	Note        string    // Note attached to the reminder
+	DeliveredAt time.Time // Time at which the reminder was delivered
+
+	// MessageSnapshot is a copy of the message that the user wanted to be reminded about, or nil if there's none
+	MessageSnapshot *MessageSnapshot
+}
@@ -0,0 +1,10 @@
+package core
+
+import "time"
+
+// FailedReminder is a reminder that could not be delivered, kept so that it isn't silently lost
+type FailedReminder struct {
+	Reminder
+	Error    string    // Error that caused the last delivery attempt to fail
+	FailedAt time.Time // Time at which the reminder was given up on
+}
@@ -0,0 +1,87 @@
+package core
+
+import "time"
+
+type GuildSettings struct {
+	GuildID            string // ID of the guild the settings belong to
+	RemindOthersRoleID string // ID of the role allowed to remind other users and roles, or empty if only moderators may
+	Language           string // ISO 639-1 code of the default language of the guild's members, or empty if the guild has not selected one
+
+	CommandPrefix             string        // Prefix of the bot's commands in the guild, or empty to use the bot's default prefix
+	ReactionReminderDuration  time.Duration // In how long reminders created by reacting to a message are due, or 0 to use the bot's default
+	MaximumRemindersPerUser   int           // Maximum number of reminders a member can have when creating one from the guild, or 0 to use the bot's default
+	AllowedChannelIDs         []string      // IDs of the channels in which the bot can be used, or empty if it can be used in every channel
+	InChannelDeliveryDisabled bool          // Whether reminders created from the guild must be delivered by direct message
+}
+
+// AllowsRemindingOthers returns whether a member with the roles passed as parameter is allowed to remind other users and
+// roles. Since the ID of the @everyone role is the ID of its guild, setting RemindOthersRoleID to the guild's ID allows
+// everybody to remind others.
+func (s *GuildSettings) AllowsRemindingOthers(memberRoleIDs []string) bool {
+	if s == nil || len(s.RemindOthersRoleID) == 0 {
+		return false
+	}
+	if s.RemindOthersRoleID == s.GuildID {
+		return true
+	}
+	for _, roleID := range memberRoleIDs {
+		if roleID == s.RemindOthersRoleID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLanguage returns the language selected, or an empty string if none was selected or if the settings are nil
+func (s *GuildSettings) GetLanguage() string {
+	if s == nil {
+		return ""
+	}
+	return s.Language
+}
+
+// GetCommandPrefix returns the prefix of the bot's commands in the guild, or defaultPrefix if the guild has not
+// configured one or if the settings are nil
+func (s *GuildSettings) GetCommandPrefix(defaultPrefix string) string {
+	if s == nil || len(s.CommandPrefix) == 0 {
+		return defaultPrefix
+	}
+	return s.CommandPrefix
+}
+
+// GetReactionReminderDuration returns in how long reminders created by reacting to a message are due, or
+// defaultDuration if the guild has not configured it or if the settings are nil
+func (s *GuildSettings) GetReactionReminderDuration(defaultDuration time.Duration) time.Duration {
+	if s == nil || s.ReactionReminderDuration <= 0 {
+		return defaultDuration
+	}
+	return s.ReactionReminderDuration
+}
+
+// GetMaximumRemindersPerUser returns the maximum number of reminders a member can have when creating one from the
+// guild, or defaultMaximum if the guild has not configured it or if the settings are nil
+func (s *GuildSettings) GetMaximumRemindersPerUser(defaultMaximum int) int {
+	if s == nil || s.MaximumRemindersPerUser <= 0 {
+		return defaultMaximum
+	}
+	return s.MaximumRemindersPerUser
+}
+
+// AllowsChannel returns whether the bot can be used in a channel of the guild.
+// Every channel is allowed if the guild has not restricted the bot to specific channels or if the settings are nil.
+func (s *GuildSettings) AllowsChannel(channelID string) bool {
+	if s == nil || len(s.AllowedChannelIDs) == 0 {
+		return true
+	}
+	for _, allowedChannelID := range s.AllowedChannelIDs {
+		if allowedChannelID == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsInChannelDelivery returns whether reminders created from the guild can be delivered in a channel
+func (s *GuildSettings) AllowsInChannelDelivery() bool {
+	return s == nil || !s.InChannelDeliveryDisabled
+}
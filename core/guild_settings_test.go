@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuildSettings_AllowsRemindingOthers(t *testing.T) {
+	var settings *GuildSettings
+	if settings.AllowsRemindingOthers([]string{"1"}) {
+		t.Error("expected nil settings to only allow moderators to remind others")
+	}
+	settings = &GuildSettings{GuildID: "100", RemindOthersRoleID: "1"}
+	if !settings.AllowsRemindingOthers([]string{"2", "1"}) {
+		t.Error("expected members with role 1 to be allowed to remind others")
+	}
+	if settings.AllowsRemindingOthers([]string{"2"}) {
+		t.Error("expected members without role 1 not to be allowed to remind others")
+	}
+	settings.RemindOthersRoleID = "100"
+	if !settings.AllowsRemindingOthers(nil) {
+		t.Error("expected everybody to be allowed to remind others when the role is @everyone")
+	}
+}
+
+func TestGuildSettings_Defaults(t *testing.T) {
+	var settings *GuildSettings
+	if settings.GetCommandPrefix("!") != "!" || settings.GetReactionReminderDuration(time.Hour) != time.Hour || settings.GetMaximumRemindersPerUser(35) != 35 {
+		t.Error("expected nil settings to use the defaults")
+	}
+	if !settings.AllowsChannel("1") || !settings.AllowsInChannelDelivery() {
+		t.Error("expected nil settings to allow every channel and in-channel delivery")
+	}
+	settings = &GuildSettings{GuildID: "100", CommandPrefix: "?", ReactionReminderDuration: 2 * time.Hour, MaximumRemindersPerUser: 5, AllowedChannelIDs: []string{"1", "2"}, InChannelDeliveryDisabled: true}
+	if settings.GetCommandPrefix("!") != "?" || settings.GetReactionReminderDuration(time.Hour) != 2*time.Hour || settings.GetMaximumRemindersPerUser(35) != 5 {
+		t.Error("expected the settings of the guild to override the defaults")
+	}
+	if !settings.AllowsChannel("2") || settings.AllowsChannel("3") {
+		t.Error("expected only channels 1 and 2 to be allowed")
+	}
+	if settings.AllowsInChannelDelivery() {
+		t.Error("expected in-channel delivery to be disabled")
+	}
+}
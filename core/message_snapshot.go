@@ -0,0 +1,59 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/TwiN/discord-reminder-bot/i18n"
+)
+
+const (
+	// MaximumMessageSnapshotContentLength is the maximum number of characters of the content of a message kept in a
+	// MessageSnapshot
+	MaximumMessageSnapshotContentLength = 500
+
+	// MaximumMessageSnapshotAttachments is the maximum number of attachments of a message kept in a MessageSnapshot
+	MaximumMessageSnapshotAttachments = 5
+)
+
+// MessageSnapshot is a copy of the message that a reminder is about, taken when the reminder is created so that the
+// reminder still makes sense if the message is edited or deleted before the reminder is delivered
+type MessageSnapshot struct {
+	AuthorName     string   `json:"author_name"`               // Name of the author of the message
+	ChannelName    string   `json:"channel_name,omitempty"`    // Name of the channel the message was sent in, or empty for a direct message
+	Content        string   `json:"content,omitempty"`         // Excerpt of the content of the message
+	AttachmentURLs []string `json:"attachment_urls,omitempty"` // URLs of the attachments of the message
+}
+
+// NewMessageSnapshot creates a MessageSnapshot, truncating the content and the attachments of the message if necessary
+func NewMessageSnapshot(authorName, channelName, content string, attachmentURLs []string) *MessageSnapshot {
+	if runes := []rune(content); len(runes) > MaximumMessageSnapshotContentLength {
+		content = strings.TrimSpace(string(runes[:MaximumMessageSnapshotContentLength-1])) + "…"
+	}
+	if len(attachmentURLs) > MaximumMessageSnapshotAttachments {
+		attachmentURLs = attachmentURLs[:MaximumMessageSnapshotAttachments]
+	}
+	return &MessageSnapshot{
+		AuthorName:     authorName,
+		ChannelName:    channelName,
+		Content:        content,
+		AttachmentURLs: attachmentURLs,
+	}
+}
+
+// GenerateQuote generates a block quote of the message, followed by its author, its channel and its attachments
+func (s MessageSnapshot) GenerateQuote(language i18n.Language) string {
+	var quote strings.Builder
+	if len(s.Content) > 0 {
+		for _, line := range strings.Split(s.Content, "\n") {
+			quote.WriteString("> " + line + "\n")
+		}
+	}
+	for _, attachmentURL := range s.AttachmentURLs {
+		quote.WriteString("> [" + language.Translate("Attachment") + "](" + attachmentURL + ")\n")
+	}
+	quote.WriteString("> — **" + s.AuthorName + "**")
+	if len(s.ChannelName) > 0 {
+		quote.WriteString(language.Sprintf(" in #%s", s.ChannelName))
+	}
+	return quote.String()
+}
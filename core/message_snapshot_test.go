@@ -0,0 +1,47 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TwiN/discord-reminder-bot/i18n"
+)
+
+func TestNewMessageSnapshot(t *testing.T) {
+	messageSnapshot := NewMessageSnapshot("author", "general", strings.Repeat("a", MaximumMessageSnapshotContentLength+10), []string{"1", "2", "3", "4", "5", "6"})
+	if length := len([]rune(messageSnapshot.Content)); length != MaximumMessageSnapshotContentLength {
+		t.Errorf("content should've been truncated to %d characters, got %d", MaximumMessageSnapshotContentLength, length)
+	}
+	if !strings.HasSuffix(messageSnapshot.Content, "…") {
+		t.Error("truncated content should've ended with an ellipsis")
+	}
+	if len(messageSnapshot.AttachmentURLs) != MaximumMessageSnapshotAttachments {
+		t.Errorf("attachments should've been truncated to %d, got %d", MaximumMessageSnapshotAttachments, len(messageSnapshot.AttachmentURLs))
+	}
+}
+
+func TestMessageSnapshot_GenerateQuote(t *testing.T) {
+	scenarios := []struct {
+		Name            string
+		MessageSnapshot MessageSnapshot
+		ExpectedQuote   string
+	}{
+		{
+			Name:            "content",
+			MessageSnapshot: MessageSnapshot{AuthorName: "author", ChannelName: "general", Content: "first line\nsecond line"},
+			ExpectedQuote:   "> first line\n> second line\n> — **author** in #general",
+		},
+		{
+			Name:            "attachment-without-content",
+			MessageSnapshot: MessageSnapshot{AuthorName: "author", AttachmentURLs: []string{"https://cdn.discordapp.com/attachments/1/2/image.png"}},
+			ExpectedQuote:   "> [Attachment](https://cdn.discordapp.com/attachments/1/2/image.png)\n> — **author**",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if quote := scenario.MessageSnapshot.GenerateQuote(i18n.English); quote != scenario.ExpectedQuote {
+				t.Errorf("expected '%s', got '%s'", scenario.ExpectedQuote, quote)
+			}
+		})
+	}
+}
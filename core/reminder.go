@@ -4,34 +4,163 @@ import (
 	"time"
 
 	"github.com/TwiN/discord-reminder-bot/format"
+	"github.com/TwiN/discord-reminder-bot/i18n"
 )
 
 type Reminder struct {
-	ID                    int64     // ID is the ROWID automatically generated by SQLite
-	NotificationMessageID string    // ID of the message used to manage the reminder
-	UserID                string    // ID of the user to notify
-	MessageLink           string    // Link to the message that the user wants to be reminded about
-	Note                  string    // Note attached to the reminder
-	Time                  time.Time // Time at which the reminder is due for
+	ID                    int64         // ID is the ROWID automatically generated by SQLite
+	NotificationMessageID string        // ID of the message used to manage the reminder
+	CreatorID             string        // ID of the user who created the reminder, and who manages it through the notification message
+	UserID                string        // ID of the user to notify, or empty if the reminder is for a role
+	RoleID                string        // ID of the role to notify, or empty if the reminder is for a user
+	ChannelID             string        // ID of the channel or thread in which the reminder is delivered, or empty to deliver it by direct message
+	MessageLink           string        // Link to the message that the user wants to be reminded about
+	Note                  string        // Note attached to the reminder
+	Time                  time.Time     // Time at which the reminder is due for
+	RepeatInterval        time.Duration // Interval at which the reminder repeats, or 0 if the reminder does not repeat
+	RepeatIntervalMonths  int           // Number of calendar months added to RepeatInterval between occurrences of the reminder
+	RepeatUntil           time.Time     // Time after which a repeating reminder stops repeating, or zero if it repeats forever
+	RepeatOccurrences     int           // Number of occurrences left for a repeating reminder (including the next one), or 0 if unlimited
+	DeliveryAttempts      int           // Number of failed attempts at delivering the current occurrence of the reminder
+	DeliveredMessageID    string        // ID of the message the current occurrence was delivered with, or empty if it hasn't been delivered yet
+
+	// MessageSnapshot is a copy of the message that the user wants to be reminded about, or nil if the message could
+	// not be retrieved when the reminder was created
+	MessageSnapshot *MessageSnapshot
+}
+
+// IsRepeating returns whether the reminder repeats after it has been delivered
+func (r Reminder) IsRepeating() bool {
+	return r.RepeatInterval > 0 || r.RepeatIntervalMonths > 0
+}
+
+// RepeatPeriod returns the period at which the reminder repeats
+func (r Reminder) RepeatPeriod() format.Period {
+	return format.Period{Months: r.RepeatIntervalMonths, Duration: r.RepeatInterval}
+}
+
+// IsForSomeoneElse returns whether the reminder is for a user or a role other than the user who created it
+func (r Reminder) IsForSomeoneElse() bool {
+	return len(r.RoleID) > 0 || (len(r.CreatorID) > 0 && r.CreatorID != r.UserID)
+}
+
+// IsDeliveredInChannel returns whether the reminder is delivered in a channel rather than by direct message
+func (r Reminder) IsDeliveredInChannel() bool {
+	return len(r.ChannelID) > 0
+}
+
+// ScheduleNextOccurrence moves a repeating reminder to its next occurrence after now.
+// Returns false if the reminder does not repeat or if it has no occurrences left, in which case
+// the reminder should be deleted.
+func (r *Reminder) ScheduleNextOccurrence(now time.Time) bool {
+	if !r.IsRepeating() || r.RepeatOccurrences == 1 {
+		return false
+	}
+	next := r.nextOccurrenceAfter(r.Time)
+	skipped := 0
+	// If the bot was offline for a while, we don't want to send every single occurrence that was missed
+	for !next.After(now) {
+		next = r.nextOccurrenceAfter(next)
+		skipped++
+	}
+	if !r.RepeatUntil.IsZero() && next.After(r.RepeatUntil) {
+		return false
+	}
+	if r.RepeatOccurrences > 0 {
+		r.RepeatOccurrences -= 1 + skipped
+		if r.RepeatOccurrences <= 0 {
+			return false
+		}
+	}
+	r.Time = next
+	r.DeliveredMessageID = ""
+	return true
 }
 
-func (r Reminder) GenerateNotificationMessageContent() string {
-	if time.Until(r.Time) < 0 {
-		return "I will remind you about [this message](" + r.MessageLink + ") at " + r.Time.Format(time.RFC3339)
+// nextOccurrenceAfter returns the occurrence that follows t
+// Intervals that are a whole number of days are added as calendar days, so that a reminder repeating every day
+// stays at the same time of day in the location of the reminder's time even when daylight saving time changes.
+// Months are added with format.AddMonths, so a reminder repeating every month from January 31st is delivered on the
+// last day of February, and on that same day of the month from then on.
+func (r Reminder) nextOccurrenceAfter(t time.Time) time.Time {
+	t = format.AddMonths(t, r.RepeatIntervalMonths)
+	if r.RepeatInterval%(24*time.Hour) == 0 {
+		return t.AddDate(0, 0, int(r.RepeatInterval/(24*time.Hour)))
 	}
-	return "I will remind you about [this message](" + r.MessageLink + ") in " + format.PrettyDuration(time.Until(r.Time).Round(time.Second))
+	return t.Add(r.RepeatInterval)
 }
 
-func (r Reminder) GenerateReminderMessageContent() string {
-	if len(r.Note) > 0 {
-		return "You asked me to remind you about [this message](" + r.MessageLink + ") and attached the following note:\n```" + r.Note + "```"
+// GenerateNotificationMessageContent generates the content of the message through which the creator of the reminder
+// manages it
+func (r Reminder) GenerateNotificationMessageContent(language i18n.Language) string {
+	var content string
+	switch {
+	case time.Until(r.Time) < 0 && r.IsForSomeoneElse():
+		content = language.Sprintf("I will remind %s%s about [this message](%s) at %s", r.generateRecipientMention(), r.generateChannelDescription(language), r.MessageLink, r.Time.Format(time.RFC3339))
+	case time.Until(r.Time) < 0:
+		content = language.Sprintf("I will remind you%s about [this message](%s) at %s", r.generateChannelDescription(language), r.MessageLink, r.Time.Format(time.RFC3339))
+	case r.IsForSomeoneElse():
+		content = language.Sprintf("I will remind %s%s about [this message](%s) in %s", r.generateRecipientMention(), r.generateChannelDescription(language), r.MessageLink, format.PrettyDurationIn(language, time.Until(r.Time).Round(time.Second)))
+	default:
+		content = language.Sprintf("I will remind you%s about [this message](%s) in %s", r.generateChannelDescription(language), r.MessageLink, format.PrettyDurationIn(language, time.Until(r.Time).Round(time.Second)))
+	}
+	return content + r.generateRepeatDescription(language)
+}
+
+func (r Reminder) generateRecipientMention() string {
+	if len(r.RoleID) > 0 {
+		return "<@&" + r.RoleID + ">"
+	}
+	return "<@" + r.UserID + ">"
+}
+
+func (r Reminder) generateChannelDescription(language i18n.Language) string {
+	if !r.IsDeliveredInChannel() {
+		return ""
+	}
+	return language.Sprintf(" in <#%s>", r.ChannelID)
+}
+
+func (r Reminder) generateRepeatDescription(language i18n.Language) string {
+	if !r.IsRepeating() {
+		return ""
+	}
+	description := language.Sprintf(", and every %s after that", format.PrettyPeriodIn(language, r.RepeatPeriod()))
+	if !r.RepeatUntil.IsZero() {
+		description += language.Sprintf(" until %s", r.RepeatUntil.Format(time.RFC3339))
+	}
+	if r.RepeatOccurrences == 1 {
+		description += language.Translate(" (last occurrence)")
+	} else if r.RepeatOccurrences > 1 {
+		description += language.Sprintf(" (%d occurrences left)", r.RepeatOccurrences)
+	}
+	return description
+}
+
+// GenerateReminderMessageContent generates the content of the message through which the reminder is delivered
+func (r Reminder) GenerateReminderMessageContent(language i18n.Language) string {
+	var content string
+	switch {
+	case len(r.Note) > 0 && r.IsForSomeoneElse():
+		content = language.Sprintf("<@%s> asked me to remind you about [this message](%s) and attached the following note:\n```%s```", r.CreatorID, r.MessageLink, r.Note)
+	case len(r.Note) > 0:
+		content = language.Sprintf("You asked me to remind you about [this message](%s) and attached the following note:\n```%s```", r.MessageLink, r.Note)
+	case r.IsForSomeoneElse():
+		content = language.Sprintf("<@%s> asked me to remind you about this message: [this message](%s)", r.CreatorID, r.MessageLink)
+	default:
+		content = language.Sprintf("You asked me to remind you about this message: [this message](%s)", r.MessageLink)
+	}
+	if r.MessageSnapshot != nil {
+		content += "\n\n" + r.MessageSnapshot.GenerateQuote(language)
 	}
-	return "You asked me to remind you about this message: [this message](" + r.MessageLink + ")"
+	return content
 }
 
-func (r Reminder) GenerateReminderMessageContentInList(notificationMessageChannelID string) string {
+// GenerateReminderMessageContentInList generates the description of the reminder in the list of reminders of its creator
+func (r Reminder) GenerateReminderMessageContentInList(language i18n.Language, notificationMessageChannelID string) string {
+	content := "[[" + language.Translate("Edit") + "]](https://discord.com/channels/@me/" + notificationMessageChannelID + "/" + r.NotificationMessageID + ") [[" + language.Translate("Message link") + "]](" + r.MessageLink + ")"
 	if len(r.Note) > 0 {
-		return "[[Edit]](https://discord.com/channels/@me/" + notificationMessageChannelID + "/" + r.NotificationMessageID + ") [[Message link]](" + r.MessageLink + ") ```" + r.Note + "```"
+		return content + " ```" + r.Note + "```"
 	}
-	return "[[Edit]](https://discord.com/channels/@me/" + notificationMessageChannelID + "/" + r.NotificationMessageID + ") [[Message link]](" + r.MessageLink + ")"
+	return content
 }
@@ -3,6 +3,8 @@ package core
 import (
 	"testing"
 	"time"
+
+	"github.com/TwiN/discord-reminder-bot/i18n"
 )
 
 func TestReminder_GenerateNotificationMessageContent(t *testing.T) {
@@ -13,12 +15,12 @@ func TestReminder_GenerateNotificationMessageContent(t *testing.T) {
 		Note:                  "<Note>",
 		Time:                  time.Now().Add(time.Hour),
 	}
-	if expected := "I will remind you about [this message](<MessageLink>) in 1 hour"; reminder.GenerateNotificationMessageContent() != expected {
-		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateNotificationMessageContent())
+	if expected := "I will remind you about [this message](<MessageLink>) in 1 hour"; reminder.GenerateNotificationMessageContent(i18n.English) != expected {
+		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateNotificationMessageContent(i18n.English))
 	}
 	reminder.Time = time.Now().Add(26*time.Hour + 30*time.Minute)
-	if expected := "I will remind you about [this message](<MessageLink>) in 1 day, 2 hours and 30 minutes"; reminder.GenerateNotificationMessageContent() != expected {
-		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateNotificationMessageContent())
+	if expected := "I will remind you about [this message](<MessageLink>) in 1 day, 2 hours and 30 minutes"; reminder.GenerateNotificationMessageContent(i18n.English) != expected {
+		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateNotificationMessageContent(i18n.English))
 	}
 }
 
@@ -30,7 +32,138 @@ func TestReminder_GenerateReminderMessageContent(t *testing.T) {
 		Note:                  "<Note>",
 		Time:                  time.Now().Add(time.Hour),
 	}
-	if reminder.GenerateReminderMessageContent() != "You asked me to remind you about [this message](<MessageLink>) and attached the following note:\n```<Note>```" {
-		t.Error("expected 'You asked me to remind you about [this message](<MessageLink>) and attached the following note:\n```<Note>```', got", reminder.GenerateReminderMessageContent())
+	if reminder.GenerateReminderMessageContent(i18n.English) != "You asked me to remind you about [this message](<MessageLink>) and attached the following note:\n```<Note>```" {
+		t.Error("expected 'You asked me to remind you about [this message](<MessageLink>) and attached the following note:\n```<Note>```', got", reminder.GenerateReminderMessageContent(i18n.English))
+	}
+}
+
+func TestReminder_GenerateReminderMessageContentWithMessageSnapshot(t *testing.T) {
+	reminder := &Reminder{
+		UserID:          "<UserID>",
+		MessageLink:     "<MessageLink>",
+		MessageSnapshot: &MessageSnapshot{AuthorName: "<AuthorName>", Content: "<Content>"},
+	}
+	if expected := "You asked me to remind you about this message: [this message](<MessageLink>)\n\n> <Content>\n> — **<AuthorName>**"; reminder.GenerateReminderMessageContent(i18n.English) != expected {
+		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateReminderMessageContent(i18n.English))
+	}
+}
+
+func TestReminder_GenerateNotificationMessageContentWhenRepeating(t *testing.T) {
+	reminder := &Reminder{
+		MessageLink:       "<MessageLink>",
+		Time:              time.Now().Add(time.Hour),
+		RepeatInterval:    24 * time.Hour,
+		RepeatOccurrences: 3,
+	}
+	if expected := "I will remind you about [this message](<MessageLink>) in 1 hour, and every 1 day after that (3 occurrences left)"; reminder.GenerateNotificationMessageContent(i18n.English) != expected {
+		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateNotificationMessageContent(i18n.English))
+	}
+}
+
+func TestReminder_GenerateNotificationMessageContentWhenDeliveredInChannel(t *testing.T) {
+	reminder := &Reminder{
+		MessageLink: "<MessageLink>",
+		ChannelID:   "123",
+		Time:        time.Now().Add(time.Hour),
+	}
+	if expected := "I will remind you in <#123> about [this message](<MessageLink>) in 1 hour"; reminder.GenerateNotificationMessageContent(i18n.English) != expected {
+		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateNotificationMessageContent(i18n.English))
+	}
+}
+
+func TestReminder_GenerateMessageContentWhenForSomeoneElse(t *testing.T) {
+	reminder := &Reminder{
+		CreatorID:   "1",
+		UserID:      "2",
+		MessageLink: "<MessageLink>",
+		Time:        time.Now().Add(time.Hour),
+	}
+	if expected := "I will remind <@2> about [this message](<MessageLink>) in 1 hour"; reminder.GenerateNotificationMessageContent(i18n.English) != expected {
+		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateNotificationMessageContent(i18n.English))
+	}
+	if expected := "<@1> asked me to remind you about this message: [this message](<MessageLink>)"; reminder.GenerateReminderMessageContent(i18n.English) != expected {
+		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateReminderMessageContent(i18n.English))
+	}
+	reminder.UserID, reminder.RoleID, reminder.ChannelID = "", "3", "4"
+	if expected := "I will remind <@&3> in <#4> about [this message](<MessageLink>) in 1 hour"; reminder.GenerateNotificationMessageContent(i18n.English) != expected {
+		t.Errorf("expected '%s', got '%s'", expected, reminder.GenerateNotificationMessageContent(i18n.English))
+	}
+	reminder.UserID, reminder.RoleID = "1", ""
+	if reminder.IsForSomeoneElse() {
+		t.Error("a reminder created by its own recipient should not be for someone else")
+	}
+}
+
+func TestReminder_ScheduleNextOccurrence(t *testing.T) {
+	now := time.Now()
+	reminder := &Reminder{Time: now.Add(-time.Minute)}
+	if reminder.ScheduleNextOccurrence(now) {
+		t.Error("a reminder that does not repeat should not have a next occurrence")
+	}
+	reminder.RepeatInterval = time.Hour
+	reminder.DeliveredMessageID = "1"
+	if !reminder.ScheduleNextOccurrence(now) {
+		t.Fatal("a repeating reminder without end condition should have a next occurrence")
+	}
+	if expected := now.Add(59 * time.Minute); !reminder.Time.Equal(expected) {
+		t.Errorf("expected next occurrence to be at %s, got %s", expected, reminder.Time)
+	}
+	if len(reminder.DeliveredMessageID) > 0 {
+		t.Error("the next occurrence should not have been delivered yet")
+	}
+}
+
+func TestReminder_ScheduleNextOccurrenceSkipsMissedOccurrences(t *testing.T) {
+	now := time.Now()
+	reminder := &Reminder{Time: now.Add(-150 * time.Minute), RepeatInterval: time.Hour, RepeatOccurrences: 5}
+	if !reminder.ScheduleNextOccurrence(now) {
+		t.Fatal("reminder should've had a next occurrence")
+	}
+	if expected := now.Add(30 * time.Minute); !reminder.Time.Equal(expected) {
+		t.Errorf("expected next occurrence to be at %s, got %s", expected, reminder.Time)
+	}
+	if reminder.RepeatOccurrences != 2 {
+		t.Errorf("expected 2 occurrences left, got %d", reminder.RepeatOccurrences)
+	}
+}
+
+func TestReminder_ScheduleNextOccurrenceWithEndCondition(t *testing.T) {
+	now := time.Now()
+	reminder := &Reminder{Time: now, RepeatInterval: time.Hour, RepeatOccurrences: 1}
+	if reminder.ScheduleNextOccurrence(now) {
+		t.Error("reminder should not have had a next occurrence, because it was the last occurrence")
+	}
+	reminder = &Reminder{Time: now, RepeatInterval: time.Hour, RepeatUntil: now.Add(30 * time.Minute)}
+	if reminder.ScheduleNextOccurrence(now) {
+		t.Error("reminder should not have had a next occurrence, because the next occurrence is after RepeatUntil")
+	}
+}
+
+func TestReminder_ScheduleNextOccurrenceAcrossDaylightSavingTime(t *testing.T) {
+	location, _ := time.LoadLocation("America/Montreal")
+	// Daylight saving time ends on November 1st, 2026 in Montreal
+	reminder := &Reminder{Time: time.Date(2026, 10, 31, 9, 0, 0, 0, location), RepeatInterval: 24 * time.Hour}
+	if !reminder.ScheduleNextOccurrence(reminder.Time) {
+		t.Fatal("reminder should've had a next occurrence")
+	}
+	if expected := time.Date(2026, 11, 1, 9, 0, 0, 0, location); !reminder.Time.Equal(expected) {
+		t.Errorf("expected next occurrence to be at %s, got %s", expected, reminder.Time)
+	}
+}
+
+func TestReminder_ScheduleNextOccurrenceWithMonths(t *testing.T) {
+	location, _ := time.LoadLocation("America/Montreal")
+	reminder := &Reminder{Time: time.Date(2027, 1, 31, 9, 0, 0, 0, location), RepeatIntervalMonths: 1}
+	if !reminder.IsRepeating() {
+		t.Fatal("reminder repeating every month should've been repeating")
+	}
+	if !reminder.ScheduleNextOccurrence(reminder.Time) {
+		t.Fatal("reminder should've had a next occurrence")
+	}
+	if expected := time.Date(2027, 2, 28, 9, 0, 0, 0, location); !reminder.Time.Equal(expected) {
+		t.Errorf("expected next occurrence to be at %s, got %s", expected, reminder.Time)
+	}
+	if description := reminder.generateRepeatDescription(i18n.English); description != ", and every 1 month after that" {
+		t.Errorf("expected repeat description to mention the month, got %s", description)
 	}
 }
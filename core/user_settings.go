@@ -0,0 +1,31 @@
+package core
+
+import "time"
+
+type UserSettings struct {
+	UserID                      string // ID of the user the settings belong to
+	Timezone                    string // IANA name of the user's timezone (e.g. America/Montreal), or empty if the user has not set one
+	RemindersFromOthersDisabled bool   // Whether the user opted out of reminders created by other users
+	Language                    string // ISO 639-1 code of the language in which the bot responds to the user, or empty if the user has not selected one
+}
+
+// Location returns the location of the user's timezone.
+// If the user has not set a timezone or if the timezone is invalid, time.Local is returned instead.
+func (s *UserSettings) Location() *time.Location {
+	if s == nil || len(s.Timezone) == 0 {
+		return time.Local
+	}
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// GetLanguage returns the language selected, or an empty string if none was selected or if the settings are nil
+func (s *UserSettings) GetLanguage() string {
+	if s == nil {
+		return ""
+	}
+	return s.Language
+}
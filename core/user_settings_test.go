@@ -0,0 +1,21 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserSettings_Location(t *testing.T) {
+	var settings *UserSettings
+	if settings.Location() != time.Local {
+		t.Error("expected nil settings to use the local timezone")
+	}
+	settings = &UserSettings{UserID: "1", Timezone: "Asia/Tokyo"}
+	if settings.Location().String() != "Asia/Tokyo" {
+		t.Error("expected Asia/Tokyo, got", settings.Location().String())
+	}
+	settings.Timezone = "Invalid/Timezone"
+	if settings.Location() != time.Local {
+		t.Error("expected an invalid timezone to fall back to the local timezone")
+	}
+}
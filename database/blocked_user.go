@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/metrics"
+)
+
+// BlockUser prevents a user from creating reminders for another user
+func (store *SQLStore) BlockUser(ctx context.Context, userID, blockedUserID string) error {
+	start := time.Now()
+	defer metrics.ObserveDatabaseQuery("BlockUser", start)
+	_, err := store.db.Exec("INSERT INTO blocked_user (user_id, blocked_user_id) VALUES ($1, $2) ON CONFLICT(user_id, blocked_user_id) DO NOTHING", userID, blockedUserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[database][BlockUser] Failed to block user", "blocked_user_id", blockedUserID, "user_id", userID, "error", err, "duration_ms", time.Since(start).Milliseconds())
+	} else {
+		slog.DebugContext(ctx, "[database][BlockUser] Blocked user", "blocked_user_id", blockedUserID, "user_id", userID, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return err
+}
+
+// UnblockUser allows a user that was previously blocked to create reminders for another user again
+func (store *SQLStore) UnblockUser(ctx context.Context, userID, blockedUserID string) error {
+	start := time.Now()
+	defer metrics.ObserveDatabaseQuery("UnblockUser", start)
+	_, err := store.db.Exec("DELETE FROM blocked_user WHERE user_id = $1 AND blocked_user_id = $2", userID, blockedUserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[database][UnblockUser] Failed to unblock user", "blocked_user_id", blockedUserID, "user_id", userID, "error", err, "duration_ms", time.Since(start).Milliseconds())
+	} else {
+		slog.DebugContext(ctx, "[database][UnblockUser] Unblocked user", "blocked_user_id", blockedUserID, "user_id", userID, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return err
+}
+
+// IsUserBlocked returns whether a user has blocked another user
+func (store *SQLStore) IsUserBlocked(ctx context.Context, userID, blockedUserID string) (bool, error) {
+	defer metrics.ObserveDatabaseQuery("IsUserBlocked", time.Now())
+	rows, err := store.db.Query("SELECT COUNT(1) FROM blocked_user WHERE user_id = $1 AND blocked_user_id = $2", userID, blockedUserID)
+	if err != nil {
+		return false, err
+	}
+	var count int
+	for rows.Next() {
+		_ = rows.Scan(&count)
+		break
+	}
+	_ = rows.Close()
+	return count > 0, nil
+}
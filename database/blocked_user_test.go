@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBlockUser(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	if blocked, _ := store.IsUserBlocked(context.Background(), "1", "2"); blocked {
+		t.Fatal("user 2 should not have been blocked by user 1 yet")
+	}
+	if err := store.BlockUser(context.Background(), "1", "2"); err != nil {
+		t.Fatal("failed to block user:", err.Error())
+	}
+	if err := store.BlockUser(context.Background(), "1", "2"); err != nil {
+		t.Fatal("blocking a user that is already blocked should not fail:", err.Error())
+	}
+	if blocked, _ := store.IsUserBlocked(context.Background(), "1", "2"); !blocked {
+		t.Fatal("user 2 should've been blocked by user 1")
+	}
+	if blocked, _ := store.IsUserBlocked(context.Background(), "2", "1"); blocked {
+		t.Fatal("user 1 should not have been blocked by user 2")
+	}
+	if err := store.UnblockUser(context.Background(), "1", "2"); err != nil {
+		t.Fatal("failed to unblock user:", err.Error())
+	}
+	if blocked, _ := store.IsUserBlocked(context.Background(), "1", "2"); blocked {
+		t.Fatal("user 2 should no longer have been blocked by user 1")
+	}
+}
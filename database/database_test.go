@@ -1,17 +1,47 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/TwiN/discord-reminder-bot/core"
 )
 
+// newTestDatabase returns the driver and the path of an empty database for a test. SQLite is used unless
+// TEST_POSTGRES_URL is set, in which case the PostgreSQL database it points to is wiped and used instead.
+func newTestDatabase(t *testing.T) (databaseDriver, path string) {
+	databaseDriver, path = DriverSQLite, t.TempDir()+"/test.db"
+	if postgresURL := os.Getenv("TEST_POSTGRES_URL"); len(postgresURL) > 0 {
+		databaseDriver, path = DriverPostgres, postgresURL
+		postgres, err := sql.Open(databaseDriver, path)
+		if err != nil {
+			t.Fatal("failed to open postgres database:", err.Error())
+		}
+		if _, err = postgres.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public"); err != nil {
+			t.Fatal("failed to wipe postgres database:", err.Error())
+		}
+		_ = postgres.Close()
+	}
+	return databaseDriver, path
+}
+
+// initializeTestDatabase opens an empty database for a test
+func initializeTestDatabase(t *testing.T) *SQLStore {
+	store, err := Open(newTestDatabase(t))
+	if err != nil {
+		t.Fatal("failed to initialize database:", err.Error())
+	}
+	return store
+}
+
 func TestCreateReminder(t *testing.T) {
-	Initialize("sqlite", t.TempDir()+"/test.db")
-	defer db.Close()
+	store := initializeTestDatabase(t)
+	defer store.Close()
 	targetTime := time.Now().Round(time.Minute)
-	err := CreateReminder(&core.Reminder{
+	err := store.CreateReminder(context.Background(), &core.Reminder{
 		NotificationMessageID: "1",
 		UserID:                "2",
 		MessageLink:           "3",
@@ -21,7 +51,7 @@ func TestCreateReminder(t *testing.T) {
 	if err != nil {
 		t.Fatal("failed to create reminder:", err.Error())
 	}
-	reminder, err := GetReminderByNotificationMessageID("1")
+	reminder, err := store.GetReminderByNotificationMessageID(context.Background(), "1")
 	if err != nil {
 		t.Fatal("failed to retrieve reminder by notification message id:", err.Error())
 	}
@@ -46,9 +76,9 @@ func TestCreateReminder(t *testing.T) {
 }
 
 func TestDeleteReminderByNotificationMessageID(t *testing.T) {
-	Initialize("sqlite", t.TempDir()+"/test.db")
-	defer db.Close()
-	err := CreateReminder(&core.Reminder{
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	err := store.CreateReminder(context.Background(), &core.Reminder{
 		NotificationMessageID: "1",
 		UserID:                "2",
 		MessageLink:           "3",
@@ -58,18 +88,18 @@ func TestDeleteReminderByNotificationMessageID(t *testing.T) {
 	if err != nil {
 		t.Fatal("failed to create reminder:", err.Error())
 	}
-	reminder, err := GetReminderByNotificationMessageID("1")
+	reminder, err := store.GetReminderByNotificationMessageID(context.Background(), "1")
 	if err != nil {
 		t.Fatal("failed to retrieve reminder by notification message id:", err.Error())
 	}
 	if reminder == nil {
 		t.Fatal("couldn't find reminder by notification message id from the database")
 	}
-	err = DeleteReminderByNotificationMessageID("1")
+	err = store.DeleteReminderByNotificationMessageID(context.Background(), "1")
 	if err != nil {
 		t.Fatal("failed to delete reminder by notification message id:", err.Error())
 	}
-	reminder, err = GetReminderByNotificationMessageID("1")
+	reminder, err = store.GetReminderByNotificationMessageID(context.Background(), "1")
 	if err != nil {
 		t.Fatal("failed to retrieve reminder by notification message id:", err.Error())
 	}
@@ -79,10 +109,10 @@ func TestDeleteReminderByNotificationMessageID(t *testing.T) {
 }
 
 func TestUpdateReminder(t *testing.T) {
-	Initialize("sqlite", t.TempDir()+"/test.db")
-	defer db.Close()
+	store := initializeTestDatabase(t)
+	defer store.Close()
 	now := time.Now().Round(time.Minute)
-	err := CreateReminder(&core.Reminder{
+	err := store.CreateReminder(context.Background(), &core.Reminder{
 		NotificationMessageID: "1",
 		UserID:                "2",
 		MessageLink:           "3",
@@ -92,7 +122,7 @@ func TestUpdateReminder(t *testing.T) {
 	if err != nil {
 		t.Fatal("failed to create reminder:", err.Error())
 	}
-	reminder, err := GetReminderByNotificationMessageID("1")
+	reminder, err := store.GetReminderByNotificationMessageID(context.Background(), "1")
 	if err != nil {
 		t.Fatal("failed to retrieve reminder by notification message id:", err.Error())
 	}
@@ -101,11 +131,13 @@ func TestUpdateReminder(t *testing.T) {
 	}
 	reminder.Note = "updated-note"
 	reminder.Time = reminder.Time.Add(time.Hour)
-	err = UpdateReminder(reminder)
+	reminder.DeliveryAttempts = 2
+	reminder.DeliveredMessageID = "100"
+	err = store.UpdateReminder(context.Background(), reminder)
 	if err != nil {
 		t.Fatal("failed to delete reminder by notification message id:", err.Error())
 	}
-	reminder, err = GetReminderByNotificationMessageID("1")
+	reminder, err = store.GetReminderByNotificationMessageID(context.Background(), "1")
 	if err != nil {
 		t.Fatal("failed to retrieve reminder by notification message id:", err.Error())
 	}
@@ -118,16 +150,34 @@ func TestUpdateReminder(t *testing.T) {
 	if !reminder.Time.Equal(now.Add(time.Hour)) {
 		t.Fatalf("Time should've been %d, got %d", now.Add(time.Hour).Unix(), reminder.Time.Unix())
 	}
+	if reminder.DeliveryAttempts != 2 {
+		t.Fatal("DeliveryAttempts should've been 2, got", reminder.DeliveryAttempts)
+	}
+	if reminder.DeliveredMessageID != "100" {
+		t.Fatal("DeliveredMessageID should've been 100, got", reminder.DeliveredMessageID)
+	}
+}
+
+func TestPing(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	if err := store.Ping(); err != nil {
+		t.Fatal("expected database to be reachable, got", err.Error())
+	}
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", Time: time.Now()})
+	if err := store.Ping(); err != nil {
+		t.Fatal("expected database to be reachable, got", err.Error())
+	}
 }
 
 func TestCountReminders(t *testing.T) {
-	Initialize("sqlite", t.TempDir()+"/test.db")
-	defer db.Close()
+	store := initializeTestDatabase(t)
+	defer store.Close()
 	now := time.Now().Round(time.Minute)
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "1", Time: now.Add(time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "2", Time: now.Add(-3 * time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "3", Time: now.Add(-1 * time.Hour)})
-	numberOfReminders, err := CountReminders()
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", Time: now.Add(time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", Time: now.Add(-3 * time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "3", Time: now.Add(-1 * time.Hour)})
+	numberOfReminders, err := store.CountReminders(context.Background())
 	if err != nil {
 		t.Fatal("failed to retrieve the number of reminders:", err.Error())
 	}
@@ -137,13 +187,13 @@ func TestCountReminders(t *testing.T) {
 }
 
 func TestGetOverdueReminders(t *testing.T) {
-	Initialize("sqlite", t.TempDir()+"/test.db")
-	defer db.Close()
+	store := initializeTestDatabase(t)
+	defer store.Close()
 	now := time.Now().Round(time.Minute)
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "1", Time: now.Add(time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "2", Time: now.Add(-time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "3", Time: now.Add(3 * time.Hour)})
-	overdueReminders, err := GetOverdueReminders()
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", Time: now.Add(time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", Time: now.Add(-time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "3", Time: now.Add(3 * time.Hour)})
+	overdueReminders, err := store.GetOverdueReminders(context.Background())
 	if err != nil {
 		t.Fatal("failed to retrieve overdue reminders:", err.Error())
 	}
@@ -156,19 +206,19 @@ func TestGetOverdueReminders(t *testing.T) {
 }
 
 func TestGetOverdueRemindersRetrievesTheOldestOnesFirst(t *testing.T) {
-	Initialize("sqlite", t.TempDir()+"/test.db")
-	defer db.Close()
+	store := initializeTestDatabase(t)
+	defer store.Close()
 	now := time.Now().Round(time.Minute)
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "1", Time: now.Add(time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "2", Time: now.Add(-3 * time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "3", Time: now.Add(-1 * time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "4", Time: now.Add(-3 * time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "5", Time: now.Add(-3 * time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "6", Time: now.Add(-4 * time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "7", Time: now.Add(-3 * time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "8", Time: now.Add(2 * time.Hour)})
-	_ = CreateReminder(&core.Reminder{NotificationMessageID: "9", Time: now.Add(-1 * time.Hour)})
-	overdueReminders, err := GetOverdueReminders()
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", Time: now.Add(time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", Time: now.Add(-3 * time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "3", Time: now.Add(-1 * time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "4", Time: now.Add(-3 * time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "5", Time: now.Add(-3 * time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "6", Time: now.Add(-4 * time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "7", Time: now.Add(-3 * time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "8", Time: now.Add(2 * time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "9", Time: now.Add(-1 * time.Hour)})
+	overdueReminders, err := store.GetOverdueReminders(context.Background())
 	if err != nil {
 		t.Fatal("failed to retrieve overdue reminders:", err.Error())
 	}
@@ -181,3 +231,212 @@ func TestGetOverdueRemindersRetrievesTheOldestOnesFirst(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateReminderWithRepeatInterval(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	now := time.Now().Round(time.Minute)
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", Time: now})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", Time: now, RepeatInterval: 24 * time.Hour, RepeatUntil: now.Add(72 * time.Hour), RepeatOccurrences: 3})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "3", Time: now, RepeatIntervalMonths: 3})
+	reminder, err := store.GetReminderByNotificationMessageID(context.Background(), "1")
+	if err != nil {
+		t.Fatal("failed to retrieve reminder by notification message id:", err.Error())
+	}
+	if reminder.IsRepeating() || !reminder.RepeatUntil.IsZero() || reminder.RepeatOccurrences != 0 {
+		t.Fatal("reminder 1 should not have been repeating")
+	}
+	reminder, err = store.GetReminderByNotificationMessageID(context.Background(), "2")
+	if err != nil {
+		t.Fatal("failed to retrieve reminder by notification message id:", err.Error())
+	}
+	if reminder.RepeatInterval != 24*time.Hour {
+		t.Fatal("RepeatInterval should've been 24h, got", reminder.RepeatInterval)
+	}
+	if !reminder.RepeatUntil.Equal(now.Add(72 * time.Hour)) {
+		t.Fatalf("RepeatUntil should've been %d, got %d", now.Add(72*time.Hour).Unix(), reminder.RepeatUntil.Unix())
+	}
+	if reminder.RepeatOccurrences != 3 {
+		t.Fatal("RepeatOccurrences should've been 3, got", reminder.RepeatOccurrences)
+	}
+	reminder.ScheduleNextOccurrence(now)
+	if err = store.UpdateReminder(context.Background(), reminder); err != nil {
+		t.Fatal("failed to update reminder:", err.Error())
+	}
+	reminder, _ = store.GetReminderByNotificationMessageID(context.Background(), "2")
+	if reminder.RepeatOccurrences != 2 {
+		t.Fatal("RepeatOccurrences should've been 2, got", reminder.RepeatOccurrences)
+	}
+	if !reminder.Time.Equal(now.Add(24 * time.Hour)) {
+		t.Fatalf("Time should've been %d, got %d", now.Add(24*time.Hour).Unix(), reminder.Time.Unix())
+	}
+	reminder, _ = store.GetReminderByNotificationMessageID(context.Background(), "3")
+	if !reminder.IsRepeating() || reminder.RepeatInterval != 0 || reminder.RepeatIntervalMonths != 3 {
+		t.Fatal("reminder 3 should've been repeating every 3 months, got", reminder.RepeatInterval, reminder.RepeatIntervalMonths)
+	}
+}
+
+func TestCreateReminderWithChannelID(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", UserID: "2", ChannelID: "5", MessageLink: "3", Time: time.Now().Add(time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", UserID: "2", MessageLink: "3", Time: time.Now().Add(time.Hour)})
+	reminder, _ := store.GetReminderByNotificationMessageID(context.Background(), "1")
+	if reminder == nil || !reminder.IsDeliveredInChannel() || reminder.ChannelID != "5" {
+		t.Fatal("reminder should've been delivered in channel 5, got", reminder)
+	}
+	reminder, _ = store.GetReminderByNotificationMessageID(context.Background(), "2")
+	if reminder == nil || reminder.IsDeliveredInChannel() {
+		t.Fatal("reminder should've been delivered by direct message, got", reminder)
+	}
+}
+
+func TestCreateReminderWithMessageSnapshot(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	messageSnapshot := core.NewMessageSnapshot("author", "general", "deploy at 5pm", []string{"https://cdn.discordapp.com/attachments/1/2/plan.png"})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", UserID: "2", MessageLink: "3", Time: time.Now().Add(time.Hour), MessageSnapshot: messageSnapshot})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", UserID: "2", MessageLink: "3", Time: time.Now().Add(time.Hour)})
+	reminder, _ := store.GetReminderByNotificationMessageID(context.Background(), "1")
+	if reminder == nil || reminder.MessageSnapshot == nil {
+		t.Fatal("reminder should've had a message snapshot, got", reminder)
+	}
+	if reminder.MessageSnapshot.AuthorName != "author" || reminder.MessageSnapshot.ChannelName != "general" || reminder.MessageSnapshot.Content != "deploy at 5pm" || len(reminder.MessageSnapshot.AttachmentURLs) != 1 {
+		t.Error("message snapshot should've been persisted as is, got", reminder.MessageSnapshot)
+	}
+	if reminder, _ = store.GetReminderByNotificationMessageID(context.Background(), "2"); reminder == nil || reminder.MessageSnapshot != nil {
+		t.Error("reminder should not have had a message snapshot, got", reminder)
+	}
+}
+
+func TestCreateReminderForSomeoneElse(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", CreatorID: "1", UserID: "2", MessageLink: "3", Time: time.Now().Add(time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", CreatorID: "1", RoleID: "4", ChannelID: "5", MessageLink: "3", Time: time.Now().Add(time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "3", CreatorID: "2", UserID: "2", MessageLink: "3", Time: time.Now().Add(time.Hour)})
+	reminder, _ := store.GetReminderByNotificationMessageID(context.Background(), "2")
+	if reminder == nil || reminder.CreatorID != "1" || reminder.RoleID != "4" || len(reminder.UserID) != 0 {
+		t.Fatal("reminder should've been created by user 1 for role 4, got", reminder)
+	}
+	if numberOfReminders, _ := store.CountRemindersByCreatorID(context.Background(), "1"); numberOfReminders != 2 {
+		t.Error("user 1 should've created 2 reminders, got", numberOfReminders)
+	}
+	if reminders, _ := store.GetRemindersByCreatorID(context.Background(), "2", 0, 10); len(reminders) != 1 || reminders[0].NotificationMessageID != "3" {
+		t.Error("user 2 should've created only reminder 3, got", reminders)
+	}
+}
+
+func TestOpenAddsMissingColumnsToExistingSchema(t *testing.T) {
+	// Databases created before migrations were tracked can only be SQLite databases
+	path := t.TempDir() + "/test.db"
+	// Create the table as it was before repeating reminders were supported, and before migrations were tracked
+	db, _ := sql.Open("sqlite", path)
+	_, _ = db.Exec("CREATE TABLE reminder (notification_message_id VARCHAR(64) PRIMARY KEY, user_id VARCHAR(64), message_link VARCHAR(128), note VARCHAR(255), reminder_time TIMESTAMP, repeating INTEGER DEFAULT FALSE)")
+	_, _ = db.Exec("INSERT INTO reminder (notification_message_id, user_id, message_link, note, reminder_time) VALUES ('1', '2', '3', '4', $1)", time.Now())
+	db.Close()
+	store, err := Open("sqlite", path)
+	if err != nil {
+		t.Fatal("failed to initialize database:", err.Error())
+	}
+	defer store.Close()
+	reminder, err := store.GetReminderByNotificationMessageID(context.Background(), "1")
+	if err != nil {
+		t.Fatal("failed to retrieve reminder by notification message id:", err.Error())
+	}
+	if reminder == nil || reminder.UserID != "2" || reminder.IsRepeating() || reminder.IsDeliveredInChannel() {
+		t.Fatal("reminder should've existed, should not have been repeating and should've been delivered by direct message")
+	}
+	if reminder.CreatorID != "2" || reminder.IsForSomeoneElse() {
+		t.Fatal("reminder should've been created by the user it is for, got", reminder.CreatorID)
+	}
+}
+
+func TestGetOverdueRemindersWithDifferentTimezones(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	montreal, _ := time.LoadLocation("America/Montreal")
+	now := time.Now().Round(time.Minute)
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", Time: now.Add(time.Hour).In(tokyo)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", Time: now.Add(-time.Hour).In(montreal)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "3", Time: now.Add(-2 * time.Hour).In(tokyo)})
+	overdueReminders, err := store.GetOverdueReminders(context.Background())
+	if err != nil {
+		t.Fatal("failed to retrieve overdue reminders:", err.Error())
+	}
+	if len(overdueReminders) != 2 {
+		t.Fatal("2 reminders should've been overdue, got", len(overdueReminders))
+	}
+	if overdueReminders[0].NotificationMessageID != "3" || overdueReminders[1].NotificationMessageID != "2" {
+		t.Fatal("overdue reminders should've been sorted by time regardless of their timezone")
+	}
+}
+
+func TestGetUpcomingReminders(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	now := time.Now().Round(time.Minute)
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", Time: now.Add(time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", Time: now.Add(-time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "3", Time: now.Add(3 * time.Hour)})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "4", Time: now.Add(2 * time.Hour)})
+	upcomingReminders, err := store.GetUpcomingReminders(context.Background(), 3)
+	if err != nil {
+		t.Fatal("failed to retrieve upcoming reminders:", err.Error())
+	}
+	if len(upcomingReminders) != 3 {
+		t.Fatal("expected 3 reminders, got", len(upcomingReminders))
+	}
+	for i, expectedNotificationMessageID := range []string{"2", "1", "4"} {
+		if upcomingReminders[i].NotificationMessageID != expectedNotificationMessageID {
+			t.Errorf("expected reminder at index %d to have NotificationMessageID %s, got %s", i, expectedNotificationMessageID, upcomingReminders[i].NotificationMessageID)
+		}
+	}
+}
+
+func TestGetReminderByID(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", UserID: "2", Time: time.Now()})
+	reminder, err := store.GetReminderByNotificationMessageID(context.Background(), "1")
+	if err != nil || reminder == nil {
+		t.Fatal("failed to retrieve reminder by notification message id")
+	}
+	reminderByID, err := store.GetReminderByID(context.Background(), reminder.ID)
+	if err != nil {
+		t.Fatal("failed to retrieve reminder by id:", err.Error())
+	}
+	if reminderByID == nil || reminderByID.NotificationMessageID != "1" || reminderByID.UserID != "2" {
+		t.Fatal("expected reminder with NotificationMessageID 1 and UserID 2, got", reminderByID)
+	}
+	if reminder, _ = store.GetReminderByID(context.Background(), reminder.ID+1); reminder != nil {
+		t.Fatal("reminder should've been nil, because no reminder has that ID")
+	}
+}
+
+func TestOpenWithUnsupportedDriver(t *testing.T) {
+	if _, err := Open("mysql", "reminders"); err == nil {
+		t.Error("initializing a database with an unsupported driver should've failed")
+	}
+}
+
+func TestOpenMultipleDatabases(t *testing.T) {
+	first, err := Open(DriverSQLite, t.TempDir()+"/first.db")
+	if err != nil {
+		t.Fatal("failed to initialize first database:", err.Error())
+	}
+	defer first.Close()
+	second, err := Open(DriverSQLite, t.TempDir()+"/second.db")
+	if err != nil {
+		t.Fatal("failed to initialize second database:", err.Error())
+	}
+	defer second.Close()
+	_ = first.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", Time: time.Now()})
+	if reminder, _ := first.GetReminderByNotificationMessageID(context.Background(), "1"); reminder == nil {
+		t.Error("reminder should've been created in the first database")
+	}
+	if reminder, _ := second.GetReminderByNotificationMessageID(context.Background(), "1"); reminder != nil {
+		t.Error("reminder should not have been created in the second database")
+	}
+}
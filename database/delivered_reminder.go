@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/metrics"
+)
+
+// CreateDeliveredReminder keeps track of a reminder that has been delivered
+func (store *SQLStore) CreateDeliveredReminder(ctx context.Context, deliveredReminder *core.DeliveredReminder) error {
+	start := time.Now()
+	defer metrics.ObserveDatabaseQuery("CreateDeliveredReminder", start)
+	_, err := store.db.Exec(
+		"INSERT INTO delivered_reminder (message_id, user_id, channel_id, message_link, note, delivered_at, message_snapshot) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		deliveredReminder.MessageID,
+		deliveredReminder.UserID,
+		deliveredReminder.ChannelID,
+		deliveredReminder.MessageLink,
+		deliveredReminder.Note,
+		deliveredReminder.DeliveredAt.UTC(),
+		marshalMessageSnapshot(deliveredReminder.MessageSnapshot),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "[database][CreateDeliveredReminder] Failed to create delivered reminder", "message_id", deliveredReminder.MessageID, "error", err, "duration_ms", time.Since(start).Milliseconds())
+	} else {
+		slog.DebugContext(ctx, "[database][CreateDeliveredReminder] Created delivered reminder", "message_id", deliveredReminder.MessageID, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return err
+}
+
+// GetDeliveredReminderByMessageID retrieves a delivered reminder by the ID of the message through which it was delivered
+func (store *SQLStore) GetDeliveredReminderByMessageID(ctx context.Context, messageID string) (*core.DeliveredReminder, error) {
+	defer metrics.ObserveDatabaseQuery("GetDeliveredReminderByMessageID", time.Now())
+	rows, err := store.db.Query("SELECT message_id, user_id, channel_id, message_link, note, delivered_at, message_snapshot FROM delivered_reminder WHERE message_id = $1", messageID)
+	if err != nil {
+		return nil, err
+	}
+	var deliveredReminder *core.DeliveredReminder
+	for rows.Next() {
+		var messageSnapshot sql.NullString
+		deliveredReminder = &core.DeliveredReminder{}
+		_ = rows.Scan(&deliveredReminder.MessageID, &deliveredReminder.UserID, &deliveredReminder.ChannelID, &deliveredReminder.MessageLink, &deliveredReminder.Note, &deliveredReminder.DeliveredAt, &messageSnapshot)
+		deliveredReminder.MessageSnapshot = unmarshalMessageSnapshot(messageSnapshot.String)
+		break
+	}
+	_ = rows.Close()
+	return deliveredReminder, nil
+}
+
+func (store *SQLStore) DeleteDeliveredReminderByMessageID(ctx context.Context, messageID string) error {
+	start := time.Now()
+	defer metrics.ObserveDatabaseQuery("DeleteDeliveredReminderByMessageID", start)
+	_, err := store.db.Exec("DELETE FROM delivered_reminder WHERE message_id = $1", messageID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[database][DeleteDeliveredReminderByMessageID] Failed to delete delivered reminder", "message_id", messageID, "error", err, "duration_ms", time.Since(start).Milliseconds())
+	} else {
+		slog.DebugContext(ctx, "[database][DeleteDeliveredReminderByMessageID] Deleted delivered reminder", "message_id", messageID, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return err
+}
+
+// DeleteDeliveredRemindersDeliveredBefore deletes all delivered reminders that were delivered before a given time
+func (store *SQLStore) DeleteDeliveredRemindersDeliveredBefore(ctx context.Context, t time.Time) error {
+	start := time.Now()
+	defer metrics.ObserveDatabaseQuery("DeleteDeliveredRemindersDeliveredBefore", start)
+	result, err := store.db.Exec("DELETE FROM delivered_reminder WHERE delivered_at < $1", t.UTC())
+	if err != nil {
+		slog.ErrorContext(ctx, "[database][DeleteDeliveredRemindersDeliveredBefore] Failed to delete delivered reminders", "error", err, "duration_ms", time.Since(start).Milliseconds())
+		return err
+	}
+	if numberOfRowsAffected, _ := result.RowsAffected(); numberOfRowsAffected > 0 {
+		slog.DebugContext(ctx, "[database][DeleteDeliveredRemindersDeliveredBefore] Deleted delivered reminders", "count", numberOfRowsAffected, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return nil
+}
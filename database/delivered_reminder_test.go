@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+func TestCreateDeliveredReminder(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	now := time.Now().Round(time.Minute)
+	err := store.CreateDeliveredReminder(context.Background(), &core.DeliveredReminder{MessageID: "1", UserID: "2", ChannelID: "5", MessageLink: "3", Note: "4", DeliveredAt: now, MessageSnapshot: &core.MessageSnapshot{AuthorName: "6"}})
+	if err != nil {
+		t.Fatal("failed to create delivered reminder:", err.Error())
+	}
+	deliveredReminder, err := store.GetDeliveredReminderByMessageID(context.Background(), "1")
+	if err != nil {
+		t.Fatal("failed to retrieve delivered reminder by message id:", err.Error())
+	}
+	if deliveredReminder == nil {
+		t.Fatal("couldn't find delivered reminder by message id from the database")
+	}
+	if deliveredReminder.UserID != "2" || deliveredReminder.ChannelID != "5" || deliveredReminder.MessageLink != "3" || deliveredReminder.Note != "4" {
+		t.Fatal("delivered reminder should've had UserID 2, ChannelID 5, MessageLink 3 and Note 4, got", deliveredReminder)
+	}
+	if deliveredReminder.MessageSnapshot == nil || deliveredReminder.MessageSnapshot.AuthorName != "6" {
+		t.Fatal("delivered reminder should've had a message snapshot of a message from 6, got", deliveredReminder.MessageSnapshot)
+	}
+	if !deliveredReminder.DeliveredAt.Equal(now) {
+		t.Fatalf("DeliveredAt should've been %d, got %d", now.Unix(), deliveredReminder.DeliveredAt.Unix())
+	}
+	if err = store.DeleteDeliveredReminderByMessageID(context.Background(), "1"); err != nil {
+		t.Fatal("failed to delete delivered reminder by message id:", err.Error())
+	}
+	if deliveredReminder, _ = store.GetDeliveredReminderByMessageID(context.Background(), "1"); deliveredReminder != nil {
+		t.Fatal("delivered reminder should've been nil because it was deleted")
+	}
+}
+
+func TestDeleteDeliveredRemindersDeliveredBefore(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	now := time.Now().Round(time.Minute)
+	_ = store.CreateDeliveredReminder(context.Background(), &core.DeliveredReminder{MessageID: "1", DeliveredAt: now.Add(-8 * 24 * time.Hour)})
+	_ = store.CreateDeliveredReminder(context.Background(), &core.DeliveredReminder{MessageID: "2", DeliveredAt: now.Add(-time.Hour)})
+	_ = store.CreateDeliveredReminder(context.Background(), &core.DeliveredReminder{MessageID: "3", DeliveredAt: now.Add(-30 * 24 * time.Hour)})
+	if err := store.DeleteDeliveredRemindersDeliveredBefore(context.Background(), now.Add(-7*24*time.Hour)); err != nil {
+		t.Fatal("failed to delete delivered reminders:", err.Error())
+	}
+	for _, messageID := range []string{"1", "3"} {
+		if deliveredReminder, _ := store.GetDeliveredReminderByMessageID(context.Background(), messageID); deliveredReminder != nil {
+			t.Errorf("delivered reminder %s should've been deleted", messageID)
+		}
+	}
+	if deliveredReminder, _ := store.GetDeliveredReminderByMessageID(context.Background(), "2"); deliveredReminder == nil {
+		t.Error("delivered reminder 2 should not have been deleted")
+	}
+}
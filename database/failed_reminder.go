@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/metrics"
+)
+
+// MoveReminderToFailedReminders deletes a reminder that could not be delivered and keeps track of it, along with the
+// error that caused its delivery to fail, in the failed_reminder table
+func (store *SQLStore) MoveReminderToFailedReminders(ctx context.Context, reminder *core.Reminder, deliveryError string) error {
+	start := time.Now()
+	defer metrics.ObserveDatabaseQuery("MoveReminderToFailedReminders", start)
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		"INSERT INTO failed_reminder (notification_message_id, creator_id, user_id, role_id, channel_id, message_link, note, reminder_time, delivery_attempts, error, failed_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+		reminder.NotificationMessageID,
+		reminder.CreatorID,
+		reminder.UserID,
+		reminder.RoleID,
+		reminder.ChannelID,
+		reminder.MessageLink,
+		reminder.Note,
+		reminder.Time.UTC(),
+		reminder.DeliveryAttempts,
+		deliveryError,
+		time.Now().UTC(),
+	)
+	if err == nil {
+		_, err = tx.Exec("DELETE FROM reminder WHERE notification_message_id = $1", reminder.NotificationMessageID)
+	}
+	if err == nil {
+		err = tx.Commit()
+	} else {
+		_ = tx.Rollback()
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "[database][MoveReminderToFailedReminders] Failed to move reminder to failed reminders", "notification_message_id", reminder.NotificationMessageID, "error", err, "duration_ms", time.Since(start).Milliseconds())
+	} else {
+		slog.DebugContext(ctx, "[database][MoveReminderToFailedReminders] Moved reminder to failed reminders", "notification_message_id", reminder.NotificationMessageID, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return err
+}
+
+// GetFailedReminderByNotificationMessageID retrieves a reminder that could not be delivered by its NotificationMessageID
+func (store *SQLStore) GetFailedReminderByNotificationMessageID(ctx context.Context, notificationMessageID string) (*core.FailedReminder, error) {
+	defer metrics.ObserveDatabaseQuery("GetFailedReminderByNotificationMessageID", time.Now())
+	rows, err := store.db.Query("SELECT notification_message_id, creator_id, user_id, role_id, channel_id, message_link, note, reminder_time, delivery_attempts, error, failed_at FROM failed_reminder WHERE notification_message_id = $1", notificationMessageID)
+	if err != nil {
+		return nil, err
+	}
+	var failedReminder *core.FailedReminder
+	for rows.Next() {
+		failedReminder = &core.FailedReminder{}
+		_ = rows.Scan(&failedReminder.NotificationMessageID, &failedReminder.CreatorID, &failedReminder.UserID, &failedReminder.RoleID, &failedReminder.ChannelID, &failedReminder.MessageLink, &failedReminder.Note, &failedReminder.Time, &failedReminder.DeliveryAttempts, &failedReminder.Error, &failedReminder.FailedAt)
+		break
+	}
+	_ = rows.Close()
+	return failedReminder, nil
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+func TestMoveReminderToFailedReminders(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	targetTime := time.Now().Add(-time.Hour).Round(time.Minute)
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", CreatorID: "2", UserID: "2", MessageLink: "3", Note: "4", Time: targetTime})
+	reminder, _ := store.GetReminderByNotificationMessageID(context.Background(), "1")
+	reminder.DeliveryAttempts = 3
+	if err := store.MoveReminderToFailedReminders(context.Background(), reminder, "cannot send messages to this user"); err != nil {
+		t.Fatal("failed to move reminder to failed reminders:", err.Error())
+	}
+	if reminder, _ = store.GetReminderByNotificationMessageID(context.Background(), "1"); reminder != nil {
+		t.Fatal("reminder should've been deleted")
+	}
+	failedReminder, err := store.GetFailedReminderByNotificationMessageID(context.Background(), "1")
+	if err != nil {
+		t.Fatal("failed to retrieve failed reminder:", err.Error())
+	}
+	if failedReminder == nil {
+		t.Fatal("couldn't find failed reminder by notification message id from the database")
+	}
+	if failedReminder.UserID != "2" || failedReminder.Note != "4" || failedReminder.DeliveryAttempts != 3 || failedReminder.Error != "cannot send messages to this user" {
+		t.Fatal("failed reminder should've kept the reminder's fields and the error, got", failedReminder)
+	}
+	if !failedReminder.Time.Equal(targetTime) {
+		t.Fatalf("Time should've been %d, got %d", targetTime.Unix(), failedReminder.Time.Unix())
+	}
+}
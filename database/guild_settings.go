@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/metrics"
+)
+
+// GetGuildSettings retrieves the settings of a guild
+// Returns nil if the guild has never been configured
+func (store *SQLStore) GetGuildSettings(ctx context.Context, guildID string) (*core.GuildSettings, error) {
+	defer metrics.ObserveDatabaseQuery("GetGuildSettings", time.Now())
+	rows, err := store.db.Query("SELECT guild_id, remind_others_role_id, language, command_prefix, reaction_reminder_duration, maximum_reminders_per_user, allowed_channel_ids, in_channel_delivery_disabled FROM guild_settings WHERE guild_id = $1", guildID)
+	if err != nil {
+		return nil, err
+	}
+	var settings *core.GuildSettings
+	for rows.Next() {
+		settings = &core.GuildSettings{}
+		var reactionReminderDurationInSeconds int64
+		var allowedChannelIDs string
+		_ = rows.Scan(&settings.GuildID, &settings.RemindOthersRoleID, &settings.Language, &settings.CommandPrefix, &reactionReminderDurationInSeconds, &settings.MaximumRemindersPerUser, &allowedChannelIDs, &settings.InChannelDeliveryDisabled)
+		settings.ReactionReminderDuration = time.Duration(reactionReminderDurationInSeconds) * time.Second
+		if len(allowedChannelIDs) > 0 {
+			settings.AllowedChannelIDs = strings.Split(allowedChannelIDs, ",")
+		}
+		break
+	}
+	_ = rows.Close()
+	return settings, nil
+}
+
+// UpsertGuildSettings creates or updates the settings of a guild
+func (store *SQLStore) UpsertGuildSettings(ctx context.Context, settings *core.GuildSettings) error {
+	start := time.Now()
+	defer metrics.ObserveDatabaseQuery("UpsertGuildSettings", start)
+	_, err := store.db.Exec(
+		"INSERT INTO guild_settings (guild_id, remind_others_role_id, language, command_prefix, reaction_reminder_duration, maximum_reminders_per_user, allowed_channel_ids, in_channel_delivery_disabled) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT(guild_id) DO UPDATE SET remind_others_role_id = excluded.remind_others_role_id, language = excluded.language, command_prefix = excluded.command_prefix, reaction_reminder_duration = excluded.reaction_reminder_duration, maximum_reminders_per_user = excluded.maximum_reminders_per_user, allowed_channel_ids = excluded.allowed_channel_ids, in_channel_delivery_disabled = excluded.in_channel_delivery_disabled",
+		settings.GuildID,
+		settings.RemindOthersRoleID,
+		settings.Language,
+		settings.CommandPrefix,
+		int64(settings.ReactionReminderDuration.Seconds()),
+		settings.MaximumRemindersPerUser,
+		strings.Join(settings.AllowedChannelIDs, ","),
+		settings.InChannelDeliveryDisabled,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "[database][UpsertGuildSettings] Failed to update settings", "guild_id", settings.GuildID, "error", err, "duration_ms", time.Since(start).Milliseconds())
+	} else {
+		slog.DebugContext(ctx, "[database][UpsertGuildSettings] Updated settings", "guild_id", settings.GuildID, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+func TestUpsertGuildSettings(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	settings, err := store.GetGuildSettings(context.Background(), "1")
+	if err != nil {
+		t.Fatal("failed to retrieve guild settings:", err.Error())
+	}
+	if settings != nil {
+		t.Fatal("settings should've been nil, because the guild has never been configured")
+	}
+	if err = store.UpsertGuildSettings(context.Background(), &core.GuildSettings{GuildID: "1", RemindOthersRoleID: "2"}); err != nil {
+		t.Fatal("failed to create guild settings:", err.Error())
+	}
+	if err = store.UpsertGuildSettings(context.Background(), &core.GuildSettings{GuildID: "1", RemindOthersRoleID: "3", Language: "de"}); err != nil {
+		t.Fatal("failed to update guild settings:", err.Error())
+	}
+	settings, err = store.GetGuildSettings(context.Background(), "1")
+	if err != nil {
+		t.Fatal("failed to retrieve guild settings:", err.Error())
+	}
+	if settings == nil || settings.RemindOthersRoleID != "3" || settings.Language != "de" {
+		t.Fatal("RemindOthersRoleID should've been 3 and Language should've been de, got", settings)
+	}
+	if len(settings.CommandPrefix) != 0 || settings.ReactionReminderDuration != 0 || settings.MaximumRemindersPerUser != 0 || settings.AllowedChannelIDs != nil || settings.InChannelDeliveryDisabled {
+		t.Fatal("configuration should've been left to the defaults, got", settings)
+	}
+	settings.CommandPrefix = "?"
+	settings.ReactionReminderDuration = 2 * time.Hour
+	settings.MaximumRemindersPerUser = 10
+	settings.AllowedChannelIDs = []string{"4", "5"}
+	settings.InChannelDeliveryDisabled = true
+	if err = store.UpsertGuildSettings(context.Background(), settings); err != nil {
+		t.Fatal("failed to update guild settings:", err.Error())
+	}
+	settings, err = store.GetGuildSettings(context.Background(), "1")
+	if err != nil {
+		t.Fatal("failed to retrieve guild settings:", err.Error())
+	}
+	if settings.CommandPrefix != "?" || settings.ReactionReminderDuration != 2*time.Hour || settings.MaximumRemindersPerUser != 10 || strings.Join(settings.AllowedChannelIDs, ",") != "4,5" || !settings.InChannelDeliveryDisabled {
+		t.Fatal("configuration should've been persisted, got", settings)
+	}
+}
@@ -0,0 +1,310 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+// MemoryStore is a Store that keeps everything in memory, which is useful for tests and for embedding the bot in an
+// application that doesn't need reminders to survive a restart
+type MemoryStore struct {
+	reminders          map[string]*core.Reminder
+	failedReminders    map[string]*core.FailedReminder
+	userSettings       map[string]*core.UserSettings
+	guildSettings      map[string]*core.GuildSettings
+	blockedUsers       map[string]map[string]bool // IDs of the users blocked by each user
+	deliveredReminders map[string]*core.DeliveredReminder
+	lastID             int64
+	mutex              sync.RWMutex
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		reminders:          make(map[string]*core.Reminder),
+		failedReminders:    make(map[string]*core.FailedReminder),
+		userSettings:       make(map[string]*core.UserSettings),
+		guildSettings:      make(map[string]*core.GuildSettings),
+		blockedUsers:       make(map[string]map[string]bool),
+		deliveredReminders: make(map[string]*core.DeliveredReminder),
+	}
+}
+
+func (store *MemoryStore) CreateReminder(_ context.Context, reminder *core.Reminder) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, exists := store.reminders[reminder.NotificationMessageID]; exists {
+		return fmt.Errorf("reminder with NotificationMessageID=%s already exists", reminder.NotificationMessageID)
+	}
+	store.lastID++
+	storedReminder := copyReminder(reminder)
+	storedReminder.ID = store.lastID
+	store.reminders[reminder.NotificationMessageID] = storedReminder
+	return nil
+}
+
+func (store *MemoryStore) GetReminderByNotificationMessageID(_ context.Context, messageID string) (*core.Reminder, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if reminder, exists := store.reminders[messageID]; exists {
+		return copyReminder(reminder), nil
+	}
+	return nil, nil
+}
+
+func (store *MemoryStore) GetReminderByID(_ context.Context, id int64) (*core.Reminder, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	for _, reminder := range store.reminders {
+		if reminder.ID == id {
+			return copyReminder(reminder), nil
+		}
+	}
+	return nil, nil
+}
+
+func (store *MemoryStore) UpdateReminder(_ context.Context, reminder *core.Reminder) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	storedReminder, exists := store.reminders[reminder.NotificationMessageID]
+	if !exists {
+		// Like an UPDATE matching no rows, updating a reminder that doesn't exist is not an error
+		return nil
+	}
+	storedReminder.Time = reminder.Time
+	storedReminder.Note = reminder.Note
+	storedReminder.RepeatInterval = reminder.RepeatInterval
+	storedReminder.RepeatIntervalMonths = reminder.RepeatIntervalMonths
+	storedReminder.RepeatUntil = reminder.RepeatUntil
+	storedReminder.RepeatOccurrences = reminder.RepeatOccurrences
+	storedReminder.DeliveryAttempts = reminder.DeliveryAttempts
+	storedReminder.DeliveredMessageID = reminder.DeliveredMessageID
+	return nil
+}
+
+func (store *MemoryStore) DeleteReminderByNotificationMessageID(_ context.Context, messageID string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.reminders, messageID)
+	return nil
+}
+
+func (store *MemoryStore) MoveReminderToFailedReminders(_ context.Context, reminder *core.Reminder, deliveryError string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.failedReminders[reminder.NotificationMessageID] = &core.FailedReminder{
+		Reminder: *copyReminder(reminder),
+		Error:    deliveryError,
+		FailedAt: time.Now(),
+	}
+	delete(store.reminders, reminder.NotificationMessageID)
+	return nil
+}
+
+// GetFailedReminderByNotificationMessageID retrieves a reminder that could not be delivered by its
+// NotificationMessageID, or nil if there's no such reminder
+func (store *MemoryStore) GetFailedReminderByNotificationMessageID(_ context.Context, messageID string) (*core.FailedReminder, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if failedReminder, exists := store.failedReminders[messageID]; exists {
+		failedReminderCopy := *failedReminder
+		return &failedReminderCopy, nil
+	}
+	return nil, nil
+}
+
+func (store *MemoryStore) GetRemindersByCreatorID(_ context.Context, creatorID string, page, pageSize int) ([]*core.Reminder, error) {
+	reminders := store.sortedReminders(func(reminder *core.Reminder) bool {
+		return reminder.CreatorID == creatorID
+	})
+	return paginate(reminders, page*pageSize, pageSize), nil
+}
+
+func (store *MemoryStore) GetOverdueReminders(_ context.Context) ([]*core.Reminder, error) {
+	now := time.Now()
+	reminders := store.sortedReminders(func(reminder *core.Reminder) bool {
+		return reminder.Time.Before(now)
+	})
+	return paginate(reminders, 0, 5), nil
+}
+
+func (store *MemoryStore) GetUpcomingReminders(_ context.Context, limit int) ([]*core.Reminder, error) {
+	return paginate(store.sortedReminders(nil), 0, limit), nil
+}
+
+func (store *MemoryStore) CountReminders(_ context.Context) (int, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return len(store.reminders), nil
+}
+
+func (store *MemoryStore) CountRemindersByCreatorID(_ context.Context, creatorID string) (int, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	numberOfReminders := 0
+	for _, reminder := range store.reminders {
+		if reminder.CreatorID == creatorID {
+			numberOfReminders++
+		}
+	}
+	return numberOfReminders, nil
+}
+
+func (store *MemoryStore) GetUserSettings(_ context.Context, userID string) (*core.UserSettings, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if settings, exists := store.userSettings[userID]; exists {
+		settingsCopy := *settings
+		return &settingsCopy, nil
+	}
+	return nil, nil
+}
+
+func (store *MemoryStore) UpsertUserSettings(_ context.Context, settings *core.UserSettings) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	settingsCopy := *settings
+	store.userSettings[settings.UserID] = &settingsCopy
+	return nil
+}
+
+func (store *MemoryStore) GetGuildSettings(_ context.Context, guildID string) (*core.GuildSettings, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if settings, exists := store.guildSettings[guildID]; exists {
+		return copyGuildSettings(settings), nil
+	}
+	return nil, nil
+}
+
+func (store *MemoryStore) UpsertGuildSettings(_ context.Context, settings *core.GuildSettings) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.guildSettings[settings.GuildID] = copyGuildSettings(settings)
+	return nil
+}
+
+func (store *MemoryStore) BlockUser(_ context.Context, userID, blockedUserID string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.blockedUsers[userID] == nil {
+		store.blockedUsers[userID] = make(map[string]bool)
+	}
+	store.blockedUsers[userID][blockedUserID] = true
+	return nil
+}
+
+func (store *MemoryStore) UnblockUser(_ context.Context, userID, blockedUserID string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.blockedUsers[userID], blockedUserID)
+	return nil
+}
+
+func (store *MemoryStore) IsUserBlocked(_ context.Context, userID, blockedUserID string) (bool, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return store.blockedUsers[userID][blockedUserID], nil
+}
+
+func (store *MemoryStore) CreateDeliveredReminder(_ context.Context, deliveredReminder *core.DeliveredReminder) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, exists := store.deliveredReminders[deliveredReminder.MessageID]; exists {
+		return fmt.Errorf("delivered reminder with MessageID=%s already exists", deliveredReminder.MessageID)
+	}
+	store.deliveredReminders[deliveredReminder.MessageID] = copyDeliveredReminder(deliveredReminder)
+	return nil
+}
+
+func (store *MemoryStore) GetDeliveredReminderByMessageID(_ context.Context, messageID string) (*core.DeliveredReminder, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if deliveredReminder, exists := store.deliveredReminders[messageID]; exists {
+		return copyDeliveredReminder(deliveredReminder), nil
+	}
+	return nil, nil
+}
+
+func (store *MemoryStore) DeleteDeliveredReminderByMessageID(_ context.Context, messageID string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.deliveredReminders, messageID)
+	return nil
+}
+
+func (store *MemoryStore) DeleteDeliveredRemindersDeliveredBefore(_ context.Context, t time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for messageID, deliveredReminder := range store.deliveredReminders {
+		if deliveredReminder.DeliveredAt.Before(t) {
+			delete(store.deliveredReminders, messageID)
+		}
+	}
+	return nil
+}
+
+// sortedReminders returns a copy of the reminders matching filter, starting with the ones that are due the soonest.
+// If filter is nil, every reminder is returned.
+func (store *MemoryStore) sortedReminders(filter func(reminder *core.Reminder) bool) []*core.Reminder {
+	store.mutex.RLock()
+	var reminders []*core.Reminder
+	for _, reminder := range store.reminders {
+		if filter == nil || filter(reminder) {
+			reminders = append(reminders, copyReminder(reminder))
+		}
+	}
+	store.mutex.RUnlock()
+	sort.Slice(reminders, func(i, j int) bool {
+		if reminders[i].Time.Equal(reminders[j].Time) {
+			return reminders[i].ID < reminders[j].ID
+		}
+		return reminders[i].Time.Before(reminders[j].Time)
+	})
+	return reminders
+}
+
+// paginate returns at most limit reminders, skipping the first offset reminders
+func paginate(reminders []*core.Reminder, offset, limit int) []*core.Reminder {
+	if offset >= len(reminders) {
+		return nil
+	}
+	reminders = reminders[offset:]
+	if limit < len(reminders) {
+		reminders = reminders[:limit]
+	}
+	return reminders
+}
+
+// copyReminder returns a copy of a reminder, so that the reminders kept in memory can't be modified without going
+// through the store
+func copyReminder(reminder *core.Reminder) *core.Reminder {
+	reminderCopy := *reminder
+	if reminder.MessageSnapshot != nil {
+		messageSnapshotCopy := *reminder.MessageSnapshot
+		reminderCopy.MessageSnapshot = &messageSnapshotCopy
+	}
+	return &reminderCopy
+}
+
+// copyGuildSettings returns a copy of the settings of a guild
+func copyGuildSettings(settings *core.GuildSettings) *core.GuildSettings {
+	settingsCopy := *settings
+	settingsCopy.AllowedChannelIDs = append([]string(nil), settings.AllowedChannelIDs...)
+	return &settingsCopy
+}
+
+// copyDeliveredReminder returns a copy of a delivered reminder
+func copyDeliveredReminder(deliveredReminder *core.DeliveredReminder) *core.DeliveredReminder {
+	deliveredReminderCopy := *deliveredReminder
+	if deliveredReminder.MessageSnapshot != nil {
+		messageSnapshotCopy := *deliveredReminder.MessageSnapshot
+		deliveredReminderCopy.MessageSnapshot = &messageSnapshotCopy
+	}
+	return &deliveredReminderCopy
+}
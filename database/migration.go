@@ -0,0 +1,381 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// migration is a change to the schema of the database
+type migration struct {
+	description string
+	apply       func(tx *sql.Tx, driver string) error
+}
+
+// migrations are the changes to apply, in order, to an empty database to get to the latest version of the schema.
+// The version of a schema is the number of migrations that have been applied to it.
+//
+// Migrations must never be modified or reordered once released: changes to the schema must be made by appending a new
+// migration. Because databases created before migrations were tracked already have some of these changes, the
+// migrations that were released before then must remain idempotent.
+//
+// The only exception is the PostgreSQL variant of migrations, which was added to the migrations released before
+// PostgreSQL was supported (e.g. through timestampType). Such changes must leave the statements executed on SQLite
+// strictly identical, since SQLite databases may already have applied these migrations.
+var migrations = []migration{
+	{
+		description: "create reminder table",
+		apply: func(tx *sql.Tx, driver string) error {
+			if driver == DriverPostgres {
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS reminder (
+						-- PostgreSQL has no equivalent to SQLite's rowid, which is used as the ID of reminders
+						id                      BIGSERIAL UNIQUE,
+						notification_message_id VARCHAR(64) PRIMARY KEY,
+						user_id                 VARCHAR(64),
+						message_link            VARCHAR(128),
+						note                    VARCHAR(255),
+						reminder_time           TIMESTAMPTZ,
+						-- Interval in seconds at which the reminder repeats, or 0 if the reminder does not repeat
+						repeating               BIGINT DEFAULT 0
+					)
+				`)
+				return err
+			}
+			_, err := tx.Exec(`
+				CREATE TABLE IF NOT EXISTS reminder (
+					notification_message_id VARCHAR(64) PRIMARY KEY, 
+					user_id                 VARCHAR(64), 
+					message_link            VARCHAR(128),
+					note                    VARCHAR(255),
+					reminder_time           TIMESTAMP,
+					-- Interval in seconds at which the reminder repeats, or 0 (FALSE) if the reminder does not repeat
+					-- e.g. "!remindme 10h every 24h Go jog" would remind somebody "Go jog" in 10 hours from now, every 24 hours
+					repeating               INTEGER DEFAULT FALSE
+				)
+			`)
+			return err
+		},
+	},
+	{
+		description: "add end conditions to repeating reminders",
+		apply: func(tx *sql.Tx, driver string) error {
+			if err := addColumnIfNotExists(tx, driver, "reminder", "repeat_until", timestampType(driver)); err != nil {
+				return err
+			}
+			return addColumnIfNotExists(tx, driver, "reminder", "repeat_occurrences", "INTEGER DEFAULT 0")
+		},
+	},
+	{
+		description: "create user_settings table",
+		apply: func(tx *sql.Tx, driver string) error {
+			_, err := tx.Exec(`
+				CREATE TABLE IF NOT EXISTS user_settings (
+					user_id  VARCHAR(64) PRIMARY KEY,
+					timezone VARCHAR(64) DEFAULT ''
+				)
+			`)
+			return err
+		},
+	},
+	{
+		description: "create delivered_reminder table",
+		apply: func(tx *sql.Tx, driver string) error {
+			_, err := tx.Exec(`
+				CREATE TABLE IF NOT EXISTS delivered_reminder (
+					message_id   VARCHAR(64) PRIMARY KEY,
+					user_id      VARCHAR(64),
+					message_link VARCHAR(128),
+					note         VARCHAR(255),
+					delivered_at ` + timestampType(driver) + `
+				)
+			`)
+			return err
+		},
+	},
+	{
+		description: "add delivery channel to reminders",
+		apply: func(tx *sql.Tx, driver string) error {
+			// The ID of the channel in which the reminder is delivered, or empty if it is delivered by direct message
+			if err := addColumnIfNotExists(tx, driver, "reminder", "channel_id", "VARCHAR(64) DEFAULT ''"); err != nil {
+				return err
+			}
+			return addColumnIfNotExists(tx, driver, "delivered_reminder", "channel_id", "VARCHAR(64) DEFAULT ''")
+		},
+	},
+	{
+		description: "add creator and role to reminders",
+		apply: func(tx *sql.Tx, driver string) error {
+			if err := addColumnIfNotExists(tx, driver, "reminder", "creator_id", "VARCHAR(64) DEFAULT ''"); err != nil {
+				return err
+			}
+			if err := addColumnIfNotExists(tx, driver, "reminder", "role_id", "VARCHAR(64) DEFAULT ''"); err != nil {
+				return err
+			}
+			// Reminders created before users could remind others were created by the user they are for
+			if _, err := tx.Exec("UPDATE reminder SET creator_id = user_id WHERE creator_id = ''"); err != nil {
+				return err
+			}
+			if err := addColumnIfNotExists(tx, driver, "user_settings", "reminders_from_others_disabled", "BOOLEAN DEFAULT FALSE"); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`
+				CREATE TABLE IF NOT EXISTS blocked_user (
+					user_id         VARCHAR(64),
+					blocked_user_id VARCHAR(64),
+					PRIMARY KEY (user_id, blocked_user_id)
+				)
+			`)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(`
+				CREATE TABLE IF NOT EXISTS guild_settings (
+					guild_id              VARCHAR(64) PRIMARY KEY,
+					remind_others_role_id VARCHAR(64) DEFAULT ''
+				)
+			`)
+			return err
+		},
+	},
+	{
+		description: "add delivery attempts to reminders and create failed_reminder table",
+		apply: func(tx *sql.Tx, driver string) error {
+			if err := addColumnIfNotExists(tx, driver, "reminder", "delivery_attempts", "INTEGER DEFAULT 0"); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`
+				CREATE TABLE IF NOT EXISTS failed_reminder (
+					notification_message_id VARCHAR(64) PRIMARY KEY,
+					creator_id              VARCHAR(64),
+					user_id                 VARCHAR(64),
+					role_id                 VARCHAR(64),
+					channel_id              VARCHAR(64),
+					message_link            VARCHAR(128),
+					note                    VARCHAR(255),
+					reminder_time           ` + timestampType(driver) + `,
+					delivery_attempts       INTEGER,
+					error                   TEXT,
+					failed_at               ` + timestampType(driver) + `
+				)
+			`)
+			return err
+		},
+	},
+	{
+		description: "add message snapshot to reminders and delivered reminders",
+		apply: func(tx *sql.Tx, driver string) error {
+			// JSON representation of the snapshot of the message that the reminder is about, or empty if there's none
+			if err := addColumnIfNotExists(tx, driver, "reminder", "message_snapshot", "TEXT DEFAULT ''"); err != nil {
+				return err
+			}
+			return addColumnIfNotExists(tx, driver, "delivered_reminder", "message_snapshot", "TEXT DEFAULT ''")
+		},
+	},
+	{
+		description: "add calendar months to the interval of repeating reminders",
+		apply: func(tx *sql.Tx, driver string) error {
+			// Number of calendar months added to the interval in seconds at which the reminder repeats
+			return addColumnIfNotExists(tx, driver, "reminder", "repeat_interval_months", "INTEGER DEFAULT 0")
+		},
+	},
+	{
+		description: "add language to user and guild settings",
+		apply: func(tx *sql.Tx, driver string) error {
+			// ISO 639-1 code of the language in which the bot responds, or empty to fall back to the default language
+			if err := addColumnIfNotExists(tx, driver, "user_settings", "language", "VARCHAR(8) DEFAULT ''"); err != nil {
+				return err
+			}
+			return addColumnIfNotExists(tx, driver, "guild_settings", "language", "VARCHAR(8) DEFAULT ''")
+		},
+	},
+	{
+		description: "add configuration to guild settings",
+		apply: func(tx *sql.Tx, driver string) error {
+			if err := addColumnIfNotExists(tx, driver, "guild_settings", "command_prefix", "VARCHAR(16) DEFAULT ''"); err != nil {
+				return err
+			}
+			// In how many seconds reminders created by reacting to a message are due, or 0 to use the default
+			if err := addColumnIfNotExists(tx, driver, "guild_settings", "reaction_reminder_duration", "INTEGER DEFAULT 0"); err != nil {
+				return err
+			}
+			if err := addColumnIfNotExists(tx, driver, "guild_settings", "maximum_reminders_per_user", "INTEGER DEFAULT 0"); err != nil {
+				return err
+			}
+			// Comma-separated IDs of the channels in which the bot can be used, or empty if it can be used everywhere
+			if err := addColumnIfNotExists(tx, driver, "guild_settings", "allowed_channel_ids", "TEXT DEFAULT ''"); err != nil {
+				return err
+			}
+			return addColumnIfNotExists(tx, driver, "guild_settings", "in_channel_delivery_disabled", "BOOLEAN DEFAULT FALSE")
+		},
+	},
+	{
+		description: "add delivered_message_id to reminder",
+		apply: func(tx *sql.Tx, driver string) error {
+			// Set once a reminder has been sent, so that it isn't sent again if the bot stops before deleting it
+			return addColumnIfNotExists(tx, driver, "reminder", "delivered_message_id", "VARCHAR(64) DEFAULT ''")
+		},
+	},
+	{
+		description: "convert timestamps to UTC",
+		apply: func(tx *sql.Tx, driver string) error {
+			// SQLite compares timestamps as strings, so the ones persisted in the timezone of the host before times
+			// were always persisted in UTC must be rewritten, while PostgreSQL compares the instants they represent
+			if driver == DriverPostgres {
+				return nil
+			}
+			if err := convertTimestampsToUTC(tx, "reminder", "reminder_time", "repeat_until"); err != nil {
+				return err
+			}
+			if err := convertTimestampsToUTC(tx, "delivered_reminder", "delivered_at"); err != nil {
+				return err
+			}
+			return convertTimestampsToUTC(tx, "failed_reminder", "reminder_time", "failed_at")
+		},
+	},
+}
+
+// migrate applies every migration that hasn't been applied to the database yet, each in its own transaction.
+// Returns an error if the schema of the database is newer than the latest version known by this binary, since the
+// binary may not be able to use it correctly.
+func (store *SQLStore) migrate() error {
+	_, err := store.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version     INTEGER PRIMARY KEY,
+			description VARCHAR(255),
+			applied_at  ` + timestampType(store.driver) + `
+		)
+	`)
+	if err != nil {
+		return err
+	}
+	currentVersion, err := store.getSchemaVersion()
+	if err != nil {
+		return err
+	}
+	if currentVersion > len(migrations) {
+		return fmt.Errorf("schema version of the database (%d) is newer than the latest version supported (%d)", currentVersion, len(migrations))
+	}
+	for version := currentVersion + 1; version <= len(migrations); version++ {
+		start := time.Now()
+		if err = store.applyMigration(version, migrations[version-1]); err != nil {
+			slog.Error("[database][migrate] Failed to apply migration", "version", version, "description", migrations[version-1].description, "error", err)
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+		slog.Info("[database][migrate] Applied migration", "version", version, "description", migrations[version-1].description, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return nil
+}
+
+// getSchemaVersion returns the version of the schema of the database, or 0 if no migrations have been applied yet
+func (store *SQLStore) getSchemaVersion() (int, error) {
+	rows, err := store.db.Query("SELECT COALESCE(MAX(version), 0) FROM schema_version")
+	if err != nil {
+		return 0, err
+	}
+	var version int
+	for rows.Next() {
+		_ = rows.Scan(&version)
+		break
+	}
+	_ = rows.Close()
+	return version, nil
+}
+
+// applyMigration applies a migration and records its version in a single transaction, so that a migration that fails
+// halfway through leaves the database as it was
+func (store *SQLStore) applyMigration(version int, m migration) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err = m.apply(tx, store.driver); err == nil {
+		_, err = tx.Exec("INSERT INTO schema_version (version, description, applied_at) VALUES ($1, $2, $3)", version, m.description, time.Now().UTC())
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// addColumnIfNotExists adds a column to an existing table if said table doesn't already have a column with that name.
+// This is necessary, because databases created before migrations were tracked may already have the column.
+func addColumnIfNotExists(tx *sql.Tx, driver, table, column, definition string) error {
+	if driver == DriverPostgres {
+		_, err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN IF NOT EXISTS " + column + " " + definition)
+		return err
+	}
+	rows, err := tx.Query("SELECT COUNT(1) FROM pragma_table_info($1) WHERE name = $2", table, column)
+	if err != nil {
+		return err
+	}
+	var numberOfColumns int
+	for rows.Next() {
+		_ = rows.Scan(&numberOfColumns)
+		break
+	}
+	_ = rows.Close()
+	if numberOfColumns > 0 {
+		return nil
+	}
+	_, err = tx.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	return err
+}
+
+// convertTimestampsToUTC rewrites the times held by columns of a SQLite table in UTC.
+// Values that can't be parsed as times are left as they are.
+func convertTimestampsToUTC(tx *sql.Tx, table string, columns ...string) error {
+	rows, err := tx.Query("SELECT rowid, " + strings.Join(columns, ", ") + " FROM " + table)
+	if err != nil {
+		return err
+	}
+	// The rows are read before being updated, since a query can't be executed while another one is being read
+	rowValues := make(map[int64][]any)
+	for rows.Next() {
+		var rowID int64
+		values := make([]any, len(columns))
+		destinations := []any{&rowID}
+		for i := range values {
+			destinations = append(destinations, &values[i])
+		}
+		if err = rows.Scan(destinations...); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		for i, value := range values {
+			if t, ok := value.(time.Time); ok {
+				values[i] = t.UTC()
+			} else if value != nil {
+				slog.Warn("[database][convertTimestampsToUTC] Failed to parse time, leaving it as is", "table", table, "column", columns[i], "rowid", rowID)
+			}
+		}
+		rowValues[rowID] = values
+	}
+	_ = rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s = $%d", column, i+1)
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE rowid = $%d", table, strings.Join(assignments, ", "), len(columns)+1)
+	for rowID, values := range rowValues {
+		if _, err = tx.Exec(query, append(values, rowID)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timestampType returns the type of the columns holding a time
+// Times are always persisted in UTC, but PostgreSQL's TIMESTAMP would drop the timezone when reading them.
+func timestampType(driver string) string {
+	if driver == DriverPostgres {
+		return "TIMESTAMPTZ"
+	}
+	return "TIMESTAMP"
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+func TestMigrate(t *testing.T) {
+	databaseDriver, path := newTestDatabase(t)
+	store, err := Open(databaseDriver, path)
+	if err != nil {
+		t.Fatal("failed to initialize database:", err.Error())
+	}
+	if version, _ := store.getSchemaVersion(); version != len(migrations) {
+		t.Fatalf("schema version should've been %d, got %d", len(migrations), version)
+	}
+	store.Close()
+	// Opening a database that is already up to date should not apply any migration
+	if store, err = Open(databaseDriver, path); err != nil {
+		t.Fatal("failed to initialize database that is already up to date:", err.Error())
+	}
+	defer store.Close()
+	rows, err := store.db.Query("SELECT COUNT(1) FROM schema_version")
+	if err != nil {
+		t.Fatal("failed to count applied migrations:", err.Error())
+	}
+	var numberOfAppliedMigrations int
+	for rows.Next() {
+		_ = rows.Scan(&numberOfAppliedMigrations)
+	}
+	_ = rows.Close()
+	if numberOfAppliedMigrations != len(migrations) {
+		t.Fatalf("each migration should've been applied once, got %d applied migrations for %d migrations", numberOfAppliedMigrations, len(migrations))
+	}
+}
+
+func TestMigrateRefusesNewerSchema(t *testing.T) {
+	databaseDriver, path := newTestDatabase(t)
+	store, err := Open(databaseDriver, path)
+	if err != nil {
+		t.Fatal("failed to initialize database:", err.Error())
+	}
+	_, _ = store.db.Exec("INSERT INTO schema_version (version, description) VALUES ($1, 'from the future')", len(migrations)+1)
+	store.Close()
+	if store, err = Open(databaseDriver, path); err == nil {
+		store.Close()
+		t.Fatal("initializing a database whose schema is newer than the latest version supported should've failed")
+	}
+}
+
+func TestMigrateRollsBackFailedMigration(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	originalMigrations := migrations
+	defer func() {
+		migrations = originalMigrations
+	}()
+	migrations = append(migrations[:len(migrations):len(migrations)], migration{
+		description: "broken migration",
+		apply: func(tx *sql.Tx, _ string) error {
+			if _, err := tx.Exec("CREATE TABLE half_applied (id INTEGER)"); err != nil {
+				return err
+			}
+			_, err := tx.Exec("THIS IS NOT SQL")
+			return err
+		},
+	})
+	if err := store.migrate(); err == nil {
+		t.Fatal("migration should've failed")
+	}
+	if version, _ := store.getSchemaVersion(); version != len(originalMigrations) {
+		t.Errorf("schema version should've remained %d, got %d", len(originalMigrations), version)
+	}
+	if _, err := store.db.Exec("SELECT * FROM half_applied"); err == nil {
+		t.Error("changes made by the failed migration should've been rolled back")
+	}
+}
+
+func TestMigrateConvertsTimestampsToUTC(t *testing.T) {
+	// Only SQLite compares timestamps as strings
+	path := t.TempDir() + "/test.db"
+	originalMigrations := migrations
+	defer func() {
+		migrations = originalMigrations
+	}()
+	// Create the database as it was before times were always persisted in UTC
+	migrations = migrations[:len(migrations)-1]
+	store, err := Open(DriverSQLite, path)
+	if err != nil {
+		t.Fatal("failed to initialize database:", err.Error())
+	}
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	montreal, _ := time.LoadLocation("America/Montreal")
+	now := time.Now().Round(time.Second)
+	// Times used to be persisted with time.Time.String() in the timezone of the host, sometimes with a monotonic clock
+	// reading, so the overdue reminder in Tokyo used to be sorted after the upcoming one in Montreal
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1"})
+	_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2"})
+	_ = store.CreateDeliveredReminder(context.Background(), &core.DeliveredReminder{MessageID: "3"})
+	_, _ = store.db.Exec("UPDATE reminder SET reminder_time = $1 WHERE notification_message_id = '1'", now.Add(-time.Hour).In(tokyo).String()+" m=+3600.000000001")
+	_, _ = store.db.Exec("UPDATE reminder SET reminder_time = $1 WHERE notification_message_id = '2'", now.Add(time.Hour).In(montreal).String())
+	_, _ = store.db.Exec("UPDATE delivered_reminder SET delivered_at = $1 WHERE message_id = '3'", now.Add(-2*time.Hour).In(tokyo).String())
+	store.Close()
+	migrations = originalMigrations
+	if store, err = Open(DriverSQLite, path); err != nil {
+		t.Fatal("failed to migrate database:", err.Error())
+	}
+	defer store.Close()
+	overdueReminders, _ := store.GetOverdueReminders(context.Background())
+	if len(overdueReminders) != 1 || overdueReminders[0].NotificationMessageID != "1" || !overdueReminders[0].Time.Equal(now.Add(-time.Hour)) {
+		t.Fatal("only the reminder that was due an hour ago should've been overdue, got", overdueReminders)
+	}
+	upcomingReminders, _ := store.GetUpcomingReminders(context.Background(), 1)
+	if len(upcomingReminders) != 1 || upcomingReminders[0].NotificationMessageID != "1" {
+		t.Fatal("the reminder that was due an hour ago should've been the first upcoming reminder, got", upcomingReminders)
+	}
+	_ = store.DeleteDeliveredRemindersDeliveredBefore(context.Background(), now.Add(-time.Hour))
+	if deliveredReminder, _ := store.GetDeliveredReminderByMessageID(context.Background(), "3"); deliveredReminder != nil {
+		t.Error("reminder delivered two hours ago should've been deleted")
+	}
+}
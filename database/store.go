@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+// ReminderStore persists reminders
+type ReminderStore interface {
+	// CreateReminder creates a new reminder
+	CreateReminder(ctx context.Context, reminder *core.Reminder) error
+
+	// GetReminderByNotificationMessageID retrieves a reminder by its NotificationMessageID, or nil if there's no such
+	// reminder
+	GetReminderByNotificationMessageID(ctx context.Context, messageID string) (*core.Reminder, error)
+
+	// GetReminderByID retrieves a reminder by its ID, or nil if there's no such reminder
+	GetReminderByID(ctx context.Context, id int64) (*core.Reminder, error)
+
+	// UpdateReminder updates the time, the note, the fields related to repetition and the number of delivery attempts
+	// of a reminder
+	UpdateReminder(ctx context.Context, reminder *core.Reminder) error
+
+	// DeleteReminderByNotificationMessageID deletes a reminder by its NotificationMessageID
+	DeleteReminderByNotificationMessageID(ctx context.Context, messageID string) error
+
+	// MoveReminderToFailedReminders deletes a reminder that could not be delivered and keeps track of it, along with the
+	// error that caused its delivery to fail
+	MoveReminderToFailedReminders(ctx context.Context, reminder *core.Reminder, deliveryError string) error
+
+	// GetRemindersByCreatorID retrieves a page of the reminders created by a user, starting with the ones that are due
+	// the soonest
+	GetRemindersByCreatorID(ctx context.Context, creatorID string, page, pageSize int) ([]*core.Reminder, error)
+
+	// GetOverdueReminders retrieves at most 5 reminders who have exceeded the time at which said reminder was due
+	GetOverdueReminders(ctx context.Context) ([]*core.Reminder, error)
+
+	// GetUpcomingReminders retrieves at most limit reminders, starting with the ones that are due the soonest
+	GetUpcomingReminders(ctx context.Context, limit int) ([]*core.Reminder, error)
+
+	// CountReminders counts all reminders
+	CountReminders(ctx context.Context) (int, error)
+
+	// CountRemindersByCreatorID counts the reminders created by a user
+	CountRemindersByCreatorID(ctx context.Context, creatorID string) (int, error)
+}
+
+// SettingsStore persists the settings of users and guilds, as well as the users that users blocked
+type SettingsStore interface {
+	// GetUserSettings retrieves the settings of a user, or nil if the user has never configured any setting
+	GetUserSettings(ctx context.Context, userID string) (*core.UserSettings, error)
+
+	// UpsertUserSettings creates or updates the settings of a user
+	UpsertUserSettings(ctx context.Context, settings *core.UserSettings) error
+
+	// GetGuildSettings retrieves the settings of a guild, or nil if the guild has never been configured
+	GetGuildSettings(ctx context.Context, guildID string) (*core.GuildSettings, error)
+
+	// UpsertGuildSettings creates or updates the settings of a guild
+	UpsertGuildSettings(ctx context.Context, settings *core.GuildSettings) error
+
+	// BlockUser prevents a user from creating reminders for another user
+	BlockUser(ctx context.Context, userID, blockedUserID string) error
+
+	// UnblockUser allows a user that was previously blocked to create reminders for another user again
+	UnblockUser(ctx context.Context, userID, blockedUserID string) error
+
+	// IsUserBlocked returns whether a user has blocked another user
+	IsUserBlocked(ctx context.Context, userID, blockedUserID string) (bool, error)
+}
+
+// DeliveredReminderStore persists the reminders that have been delivered, so that they can be snoozed
+type DeliveredReminderStore interface {
+	// CreateDeliveredReminder keeps track of a reminder that has been delivered
+	CreateDeliveredReminder(ctx context.Context, deliveredReminder *core.DeliveredReminder) error
+
+	// GetDeliveredReminderByMessageID retrieves a delivered reminder by the ID of the message through which it was
+	// delivered, or nil if there's no such delivered reminder
+	GetDeliveredReminderByMessageID(ctx context.Context, messageID string) (*core.DeliveredReminder, error)
+
+	// DeleteDeliveredReminderByMessageID deletes a delivered reminder by the ID of the message through which it was
+	// delivered
+	DeleteDeliveredReminderByMessageID(ctx context.Context, messageID string) error
+
+	// DeleteDeliveredRemindersDeliveredBefore deletes all delivered reminders that were delivered before a given time
+	DeleteDeliveredRemindersDeliveredBefore(ctx context.Context, t time.Time) error
+}
+
+// Store persists everything the bot needs to keep across restarts
+type Store interface {
+	ReminderStore
+	SettingsStore
+	DeliveredReminderStore
+}
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+func TestReminderStore(t *testing.T) {
+	type Scenario struct {
+		Name     string
+		NewStore func(t *testing.T) ReminderStore
+	}
+	scenarios := []Scenario{
+		{
+			Name: "sql",
+			NewStore: func(t *testing.T) ReminderStore {
+				store := initializeTestDatabase(t)
+				t.Cleanup(func() {
+					store.Close()
+				})
+				return store
+			},
+		},
+		{
+			Name: "memory",
+			NewStore: func(t *testing.T) ReminderStore {
+				return NewMemoryStore()
+			},
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			store := scenario.NewStore(t)
+			now := time.Now().Round(time.Minute)
+			_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", CreatorID: "user", UserID: "user", Note: "first", Time: now.Add(time.Hour)})
+			_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "2", CreatorID: "user", UserID: "user", Time: now.Add(-time.Hour)})
+			_ = store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "3", CreatorID: "other-user", UserID: "user", Time: now.Add(2 * time.Hour)})
+			if err := store.CreateReminder(context.Background(), &core.Reminder{NotificationMessageID: "1", CreatorID: "user", UserID: "user", Time: now}); err == nil {
+				t.Error("creating a reminder with a NotificationMessageID that is already used should've failed")
+			}
+			reminder, err := store.GetReminderByNotificationMessageID(context.Background(), "1")
+			if err != nil || reminder == nil {
+				t.Fatal("reminder 1 should've existed")
+			}
+			if reminder.Note != "first" || !reminder.Time.Equal(now.Add(time.Hour)) {
+				t.Error("reminder 1 should've had the note and the time it was created with, got", reminder.Note, reminder.Time)
+			}
+			if reminderByID, _ := store.GetReminderByID(context.Background(), reminder.ID); reminderByID == nil || reminderByID.NotificationMessageID != "1" {
+				t.Error("reminder 1 should've been retrievable by its ID")
+			}
+			reminder.Note = "updated"
+			reminder.Time = now.Add(3 * time.Hour)
+			reminder.DeliveryAttempts = 2
+			reminder.DeliveredMessageID = "100"
+			if err = store.UpdateReminder(context.Background(), reminder); err != nil {
+				t.Fatal("failed to update reminder:", err.Error())
+			}
+			if reminder, _ = store.GetReminderByNotificationMessageID(context.Background(), "1"); reminder.Note != "updated" || !reminder.Time.Equal(now.Add(3*time.Hour)) || reminder.DeliveryAttempts != 2 || reminder.DeliveredMessageID != "100" {
+				t.Error("reminder 1 should've been updated, got", reminder.Note, reminder.Time, reminder.DeliveryAttempts, reminder.DeliveredMessageID)
+			}
+			if overdueReminders, _ := store.GetOverdueReminders(context.Background()); len(overdueReminders) != 1 || overdueReminders[0].NotificationMessageID != "2" {
+				t.Error("only reminder 2 should've been overdue, got", overdueReminders)
+			}
+			if upcomingReminders, _ := store.GetUpcomingReminders(context.Background(), 2); len(upcomingReminders) != 2 || upcomingReminders[0].NotificationMessageID != "2" || upcomingReminders[1].NotificationMessageID != "3" {
+				t.Error("upcoming reminders should've been reminder 2 and reminder 3, got", upcomingReminders)
+			}
+			if reminders, _ := store.GetRemindersByCreatorID(context.Background(), "user", 1, 1); len(reminders) != 1 || reminders[0].NotificationMessageID != "1" {
+				t.Error("second page of the reminders created by user should've been reminder 1, got", reminders)
+			}
+			if numberOfReminders, _ := store.CountRemindersByCreatorID(context.Background(), "user"); numberOfReminders != 2 {
+				t.Error("user should've created 2 reminders, got", numberOfReminders)
+			}
+			if err = store.MoveReminderToFailedReminders(context.Background(), reminder, "cannot send messages to this user"); err != nil {
+				t.Fatal("failed to move reminder to failed reminders:", err.Error())
+			}
+			if err = store.DeleteReminderByNotificationMessageID(context.Background(), "2"); err != nil {
+				t.Fatal("failed to delete reminder:", err.Error())
+			}
+			if numberOfReminders, _ := store.CountReminders(context.Background()); numberOfReminders != 1 {
+				t.Error("only reminder 3 should've been left, got", numberOfReminders, "reminders")
+			}
+			if reminder, _ = store.GetReminderByNotificationMessageID(context.Background(), "2"); reminder != nil {
+				t.Error("reminder 2 should've been deleted")
+			}
+		})
+	}
+}
+
+func TestStore_SettingsAndDeliveredReminders(t *testing.T) {
+	type Scenario struct {
+		Name     string
+		NewStore func(t *testing.T) Store
+	}
+	scenarios := []Scenario{
+		{
+			Name: "sql",
+			NewStore: func(t *testing.T) Store {
+				store := initializeTestDatabase(t)
+				t.Cleanup(func() {
+					store.Close()
+				})
+				return store
+			},
+		},
+		{
+			Name: "memory",
+			NewStore: func(t *testing.T) Store {
+				return NewMemoryStore()
+			},
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			store := scenario.NewStore(t)
+			ctx := context.Background()
+			if settings, _ := store.GetUserSettings(ctx, "user"); settings != nil {
+				t.Error("user should not have had settings, got", settings)
+			}
+			_ = store.UpsertUserSettings(ctx, &core.UserSettings{UserID: "user", Timezone: "America/Montreal", Language: "fr"})
+			if settings, _ := store.GetUserSettings(ctx, "user"); settings == nil || settings.Timezone != "America/Montreal" || settings.Language != "fr" {
+				t.Error("user should've had the settings upserted, got", settings)
+			}
+			if settings, _ := store.GetGuildSettings(ctx, "guild"); settings != nil {
+				t.Error("guild should not have had settings, got", settings)
+			}
+			_ = store.UpsertGuildSettings(ctx, &core.GuildSettings{GuildID: "guild", CommandPrefix: "?", ReactionReminderDuration: time.Hour, AllowedChannelIDs: []string{"1", "2"}})
+			if settings, _ := store.GetGuildSettings(ctx, "guild"); settings == nil || settings.CommandPrefix != "?" || settings.ReactionReminderDuration != time.Hour || len(settings.AllowedChannelIDs) != 2 {
+				t.Error("guild should've had the settings upserted, got", settings)
+			}
+			_ = store.BlockUser(ctx, "user", "other-user")
+			if blocked, _ := store.IsUserBlocked(ctx, "user", "other-user"); !blocked {
+				t.Error("other-user should've been blocked by user")
+			}
+			if blocked, _ := store.IsUserBlocked(ctx, "other-user", "user"); blocked {
+				t.Error("user should not have been blocked by other-user")
+			}
+			_ = store.UnblockUser(ctx, "user", "other-user")
+			if blocked, _ := store.IsUserBlocked(ctx, "user", "other-user"); blocked {
+				t.Error("other-user should've been unblocked by user")
+			}
+			now := time.Now().Round(time.Minute)
+			_ = store.CreateDeliveredReminder(ctx, &core.DeliveredReminder{MessageID: "1", UserID: "user", Note: "old", DeliveredAt: now.Add(-48 * time.Hour)})
+			_ = store.CreateDeliveredReminder(ctx, &core.DeliveredReminder{MessageID: "2", UserID: "user", Note: "recent", DeliveredAt: now, MessageSnapshot: &core.MessageSnapshot{Content: "hello"}})
+			if deliveredReminder, _ := store.GetDeliveredReminderByMessageID(ctx, "2"); deliveredReminder == nil || deliveredReminder.Note != "recent" || deliveredReminder.MessageSnapshot == nil || deliveredReminder.MessageSnapshot.Content != "hello" {
+				t.Error("delivered reminder 2 should've existed, got", deliveredReminder)
+			}
+			_ = store.DeleteDeliveredRemindersDeliveredBefore(ctx, now.Add(-24*time.Hour))
+			if deliveredReminder, _ := store.GetDeliveredReminderByMessageID(ctx, "1"); deliveredReminder != nil {
+				t.Error("delivered reminder 1 should've been purged, got", deliveredReminder)
+			}
+			_ = store.DeleteDeliveredReminderByMessageID(ctx, "2")
+			if deliveredReminder, _ := store.GetDeliveredReminderByMessageID(ctx, "2"); deliveredReminder != nil {
+				t.Error("delivered reminder 2 should've been deleted, got", deliveredReminder)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_GetFailedReminderByNotificationMessageID(t *testing.T) {
+	store := NewMemoryStore()
+	reminder := &core.Reminder{NotificationMessageID: "1", CreatorID: "user", UserID: "user", Time: time.Now()}
+	_ = store.CreateReminder(context.Background(), reminder)
+	_ = store.MoveReminderToFailedReminders(context.Background(), reminder, "cannot send messages to this user")
+	failedReminder, _ := store.GetFailedReminderByNotificationMessageID(context.Background(), "1")
+	if failedReminder == nil || failedReminder.Error != "cannot send messages to this user" {
+		t.Fatal("reminder 1 should've been moved to failed reminders, got", failedReminder)
+	}
+}
+
+func TestMemoryStore_ReturnsCopies(t *testing.T) {
+	store := NewMemoryStore()
+	reminder := &core.Reminder{NotificationMessageID: "1", Note: "original"}
+	_ = store.CreateReminder(context.Background(), reminder)
+	reminder.Note = "modified without updating"
+	if storedReminder, _ := store.GetReminderByNotificationMessageID(context.Background(), "1"); storedReminder.Note != "original" {
+		t.Error("modifying a reminder without updating it should not have modified the stored reminder, got", storedReminder.Note)
+	}
+}
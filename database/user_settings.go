@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/metrics"
+)
+
+// GetUserSettings retrieves the settings of a user
+// Returns nil if the user has never configured any setting
+func (store *SQLStore) GetUserSettings(ctx context.Context, userID string) (*core.UserSettings, error) {
+	defer metrics.ObserveDatabaseQuery("GetUserSettings", time.Now())
+	rows, err := store.db.Query("SELECT user_id, timezone, reminders_from_others_disabled, language FROM user_settings WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+	var settings *core.UserSettings
+	for rows.Next() {
+		settings = &core.UserSettings{}
+		_ = rows.Scan(&settings.UserID, &settings.Timezone, &settings.RemindersFromOthersDisabled, &settings.Language)
+		break
+	}
+	_ = rows.Close()
+	return settings, nil
+}
+
+// UpsertUserSettings creates or updates the settings of a user
+func (store *SQLStore) UpsertUserSettings(ctx context.Context, settings *core.UserSettings) error {
+	start := time.Now()
+	defer metrics.ObserveDatabaseQuery("UpsertUserSettings", start)
+	_, err := store.db.Exec(
+		"INSERT INTO user_settings (user_id, timezone, reminders_from_others_disabled, language) VALUES ($1, $2, $3, $4) ON CONFLICT(user_id) DO UPDATE SET timezone = excluded.timezone, reminders_from_others_disabled = excluded.reminders_from_others_disabled, language = excluded.language",
+		settings.UserID,
+		settings.Timezone,
+		settings.RemindersFromOthersDisabled,
+		settings.Language,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "[database][UpsertUserSettings] Failed to update settings", "user_id", settings.UserID, "error", err, "duration_ms", time.Since(start).Milliseconds())
+	} else {
+		slog.DebugContext(ctx, "[database][UpsertUserSettings] Updated settings", "user_id", settings.UserID, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return err
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+func TestUpsertUserSettings(t *testing.T) {
+	store := initializeTestDatabase(t)
+	defer store.Close()
+	settings, err := store.GetUserSettings(context.Background(), "1")
+	if err != nil {
+		t.Fatal("failed to retrieve user settings:", err.Error())
+	}
+	if settings != nil {
+		t.Fatal("settings should've been nil, because the user has never configured any")
+	}
+	if err = store.UpsertUserSettings(context.Background(), &core.UserSettings{UserID: "1", Timezone: "America/Montreal"}); err != nil {
+		t.Fatal("failed to create user settings:", err.Error())
+	}
+	if err = store.UpsertUserSettings(context.Background(), &core.UserSettings{UserID: "2", Timezone: "Europe/Paris"}); err != nil {
+		t.Fatal("failed to create user settings:", err.Error())
+	}
+	if err = store.UpsertUserSettings(context.Background(), &core.UserSettings{UserID: "1", Timezone: "Asia/Tokyo", RemindersFromOthersDisabled: true, Language: "fr"}); err != nil {
+		t.Fatal("failed to update user settings:", err.Error())
+	}
+	settings, err = store.GetUserSettings(context.Background(), "1")
+	if err != nil {
+		t.Fatal("failed to retrieve user settings:", err.Error())
+	}
+	if settings == nil || settings.Timezone != "Asia/Tokyo" || !settings.RemindersFromOthersDisabled || settings.Language != "fr" {
+		t.Fatal("Timezone should've been Asia/Tokyo, reminders from others should've been disabled and Language should've been fr, got", settings)
+	}
+	settings, _ = store.GetUserSettings(context.Background(), "2")
+	if settings == nil || settings.Timezone != "Europe/Paris" || settings.RemindersFromOthersDisabled {
+		t.Fatal("Timezone should've been Europe/Paris and reminders from others should've been enabled, got", settings)
+	}
+}
@@ -0,0 +1,167 @@
+package discord
+
+import (
+	"github.com/TwiN/discord-reminder-bot/metrics"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Client is the subset of Discord's API used by the bot
+//
+// In production, it is implemented by a Discord session wrapped with NewSessionClient, but FakeClient can be used
+// instead to exercise the bot without connecting to Discord.
+type Client interface {
+	// BotUser returns the user of the bot
+	BotUser() *discordgo.User
+
+	// Channel retrieves a channel
+	Channel(channelID string) (*discordgo.Channel, error)
+
+	// ChannelMessage retrieves a message
+	ChannelMessage(channelID, messageID string) (*discordgo.Message, error)
+
+	// GuildMember retrieves a member of a guild
+	GuildMember(guildID, userID string) (*discordgo.Member, error)
+
+	// GuildRole retrieves a role of a guild
+	GuildRole(guildID, roleID string) (*discordgo.Role, error)
+
+	// UserChannelPermissions retrieves the permissions of a user in a channel
+	UserChannelPermissions(userID, channelID string) (int64, error)
+
+	// UserChannelCreate opens a direct message channel with a user, or retrieves it if it is already opened
+	UserChannelCreate(userID string) (*discordgo.Channel, error)
+
+	// ChannelMessageSendComplex sends a message in a channel
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend) (*discordgo.Message, error)
+
+	// ChannelMessageSendReply sends a message in a channel in reply to another message
+	ChannelMessageSendReply(channelID, content string, reference *discordgo.MessageReference) (*discordgo.Message, error)
+
+	// ChannelMessageEditEmbed replaces the embed of a message
+	ChannelMessageEditEmbed(channelID, messageID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error)
+
+	// ChannelMessageEditComplex edits a message
+	ChannelMessageEditComplex(edit *discordgo.MessageEdit) (*discordgo.Message, error)
+
+	// MessageReactionAdd adds a reaction to a message as the bot
+	MessageReactionAdd(channelID, messageID, emoji string) error
+
+	// MessageReactionRemove removes the reaction of a user from a message
+	MessageReactionRemove(channelID, messageID, emoji, userID string) error
+
+	// InteractionRespond responds to an interaction
+	InteractionRespond(interaction *discordgo.Interaction, response *discordgo.InteractionResponse) error
+
+	// ApplicationCommandBulkOverwrite replaces the application commands of the bot in a guild, or its global
+	// application commands if guildID is empty
+	ApplicationCommandBulkOverwrite(guildID string, commands []*discordgo.ApplicationCommand) ([]*discordgo.ApplicationCommand, error)
+
+	// UpdateListeningStatus updates the "Listening to" status of the bot
+	UpdateListeningStatus(status string) error
+}
+
+// sessionClient is a Client that uses a Discord session, retrieving channels, messages, members and roles from the
+// session's state before falling back to the API. Errors returned by the API are counted in metrics.DiscordRESTErrors.
+type sessionClient struct {
+	session *discordgo.Session
+}
+
+// NewSessionClient creates a Client from a Discord session
+func NewSessionClient(session *discordgo.Session) Client {
+	return &sessionClient{session: session}
+}
+
+func (c *sessionClient) BotUser() *discordgo.User {
+	return c.session.State.User
+}
+
+func (c *sessionClient) Channel(channelID string) (*discordgo.Channel, error) {
+	if channel, err := c.session.State.Channel(channelID); err == nil {
+		return channel, nil
+	}
+	channel, err := c.session.Channel(channelID)
+	return channel, metrics.ObserveDiscordRESTError("Channel", err)
+}
+
+func (c *sessionClient) ChannelMessage(channelID, messageID string) (*discordgo.Message, error) {
+	if message, err := c.session.State.Message(channelID, messageID); err == nil {
+		return message, nil
+	}
+	message, err := c.session.ChannelMessage(channelID, messageID)
+	return message, metrics.ObserveDiscordRESTError("ChannelMessage", err)
+}
+
+func (c *sessionClient) GuildMember(guildID, userID string) (*discordgo.Member, error) {
+	if member, err := c.session.State.Member(guildID, userID); err == nil {
+		return member, nil
+	}
+	member, err := c.session.GuildMember(guildID, userID)
+	return member, metrics.ObserveDiscordRESTError("GuildMember", err)
+}
+
+func (c *sessionClient) GuildRole(guildID, roleID string) (*discordgo.Role, error) {
+	if role, err := c.session.State.Role(guildID, roleID); err == nil {
+		return role, nil
+	}
+	roles, err := c.session.GuildRoles(guildID)
+	if err != nil {
+		return nil, metrics.ObserveDiscordRESTError("GuildRoles", err)
+	}
+	for _, role := range roles {
+		if role.ID == roleID {
+			return role, nil
+		}
+	}
+	return nil, discordgo.ErrStateNotFound
+}
+
+func (c *sessionClient) UserChannelPermissions(userID, channelID string) (int64, error) {
+	permissions, err := c.session.UserChannelPermissions(userID, channelID)
+	return permissions, metrics.ObserveDiscordRESTError("UserChannelPermissions", err)
+}
+
+func (c *sessionClient) UserChannelCreate(userID string) (*discordgo.Channel, error) {
+	channel, err := c.session.UserChannelCreate(userID)
+	return channel, metrics.ObserveDiscordRESTError("UserChannelCreate", err)
+}
+
+func (c *sessionClient) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	message, err := c.session.ChannelMessageSendComplex(channelID, data)
+	return message, metrics.ObserveDiscordRESTError("ChannelMessageSendComplex", err)
+}
+
+func (c *sessionClient) ChannelMessageSendReply(channelID, content string, reference *discordgo.MessageReference) (*discordgo.Message, error) {
+	message, err := c.session.ChannelMessageSendReply(channelID, content, reference)
+	return message, metrics.ObserveDiscordRESTError("ChannelMessageSendReply", err)
+}
+
+func (c *sessionClient) ChannelMessageEditEmbed(channelID, messageID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	message, err := c.session.ChannelMessageEditEmbed(channelID, messageID, embed)
+	return message, metrics.ObserveDiscordRESTError("ChannelMessageEditEmbed", err)
+}
+
+func (c *sessionClient) ChannelMessageEditComplex(edit *discordgo.MessageEdit) (*discordgo.Message, error) {
+	message, err := c.session.ChannelMessageEditComplex(edit)
+	return message, metrics.ObserveDiscordRESTError("ChannelMessageEditComplex", err)
+}
+
+func (c *sessionClient) MessageReactionAdd(channelID, messageID, emoji string) error {
+	return metrics.ObserveDiscordRESTError("MessageReactionAdd", c.session.MessageReactionAdd(channelID, messageID, emoji))
+}
+
+func (c *sessionClient) MessageReactionRemove(channelID, messageID, emoji, userID string) error {
+	return metrics.ObserveDiscordRESTError("MessageReactionRemove", c.session.MessageReactionRemove(channelID, messageID, emoji, userID))
+}
+
+func (c *sessionClient) InteractionRespond(interaction *discordgo.Interaction, response *discordgo.InteractionResponse) error {
+	return metrics.ObserveDiscordRESTError("InteractionRespond", c.session.InteractionRespond(interaction, response))
+}
+
+func (c *sessionClient) ApplicationCommandBulkOverwrite(guildID string, commands []*discordgo.ApplicationCommand) ([]*discordgo.ApplicationCommand, error) {
+	commands, err := c.session.ApplicationCommandBulkOverwrite(c.session.State.User.ID, guildID, commands)
+	return commands, metrics.ObserveDiscordRESTError("ApplicationCommandBulkOverwrite", err)
+}
+
+func (c *sessionClient) UpdateListeningStatus(status string) error {
+	return c.session.UpdateListeningStatus(status)
+}
@@ -0,0 +1,281 @@
+package discord
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/database"
+	"github.com/TwiN/discord-reminder-bot/i18n"
+	"github.com/TwiN/discord-reminder-bot/logging"
+	"github.com/TwiN/discord-reminder-bot/metrics"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Custom IDs of the message components sent by the bot
+const (
+	ComponentRefreshReminder        = "reminder:refresh"
+	ComponentIncreaseReminderByHour = "reminder:+1h"
+	ComponentDecreaseReminderByHour = "reminder:-1h"
+	ComponentIncreaseReminderByDay  = "reminder:+1d"
+	ComponentDeleteReminder         = "reminder:delete"
+
+	// ComponentSnoozeDeliveredReminderPrefix is followed by the time expression to snooze the delivered reminder until
+	ComponentSnoozeDeliveredReminderPrefix = "delivered:snooze:"
+	// ComponentSnoozeDeliveredReminderCustom opens a modal asking the user until when the reminder should be snoozed
+	ComponentSnoozeDeliveredReminderCustom = "delivered:snooze-custom"
+	// ModalSnoozeDeliveredReminder is the modal asking the user until when the reminder should be snoozed
+	ModalSnoozeDeliveredReminder = "delivered:snooze-modal"
+	// ModalSnoozeDeliveredReminderTime is the text input of ModalSnoozeDeliveredReminder
+	ModalSnoozeDeliveredReminderTime = "time"
+
+	// ComponentListPagePrefix is followed by the page of reminders to display
+	ComponentListPagePrefix = "list:page:"
+)
+
+// snoozeOptions are the English labels and time expressions offered to snooze a delivered reminder
+var snoozeOptions = [][2]string{
+	{"10m", "in 10m"},
+	{"1h", "in 1h"},
+	{"3h", "in 3h"},
+	{"Tomorrow", "tomorrow 9am"},
+}
+
+// generateNotificationMessageComponents generates the buttons used to manage a reminder from its notification message
+func generateNotificationMessageComponents(language i18n.Language) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: language.Translate("Refresh"), Emoji: &discordgo.ComponentEmoji{Name: EmojiRefreshDuration}, Style: discordgo.SecondaryButton, CustomID: ComponentRefreshReminder},
+				discordgo.Button{Label: "+1h", Style: discordgo.PrimaryButton, CustomID: ComponentIncreaseReminderByHour},
+				discordgo.Button{Label: "-1h", Style: discordgo.PrimaryButton, CustomID: ComponentDecreaseReminderByHour},
+				discordgo.Button{Label: "+1d", Style: discordgo.PrimaryButton, CustomID: ComponentIncreaseReminderByDay},
+				discordgo.Button{Label: language.Translate("Delete"), Emoji: &discordgo.ComponentEmoji{Name: EmojiDeleteReminder}, Style: discordgo.DangerButton, CustomID: ComponentDeleteReminder},
+			},
+		},
+	}
+}
+
+// generateDeliveredReminderComponents generates the buttons attached to a delivered reminder
+func generateDeliveredReminderComponents(language i18n.Language) []discordgo.MessageComponent {
+	var buttons []discordgo.MessageComponent
+	for _, option := range snoozeOptions {
+		buttons = append(buttons, discordgo.Button{Label: language.Translate(option[0]), Style: discordgo.SecondaryButton, CustomID: ComponentSnoozeDeliveredReminderPrefix + option[1]})
+	}
+	buttons = append(buttons, discordgo.Button{Label: language.Translate("Custom"), Emoji: &discordgo.ComponentEmoji{Name: reminderEmojis[0]}, Style: discordgo.SecondaryButton, CustomID: ComponentSnoozeDeliveredReminderCustom})
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// generateReminderListComponents generates the buttons used to navigate between the pages of a list of reminders
+func generateReminderListComponents(language i18n.Language, page, numberOfPages int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: language.Translate("Previous"), Style: discordgo.SecondaryButton, CustomID: ComponentListPagePrefix + strconv.Itoa(page-1), Disabled: page <= 1},
+				discordgo.Button{Label: language.Translate("Next"), Style: discordgo.SecondaryButton, CustomID: ComponentListPagePrefix + strconv.Itoa(page+1), Disabled: page >= numberOfPages},
+			},
+		},
+	}
+}
+
+func handleComponentInteraction(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate) {
+	customID := interaction.MessageComponentData().CustomID
+	switch {
+	case strings.HasPrefix(customID, "reminder:"):
+		handleReminderComponent(ctx, bot, store, interaction, customID)
+	case strings.HasPrefix(customID, ComponentListPagePrefix):
+		page, err := strconv.Atoi(strings.TrimPrefix(customID, ComponentListPagePrefix))
+		if err != nil || page < 1 {
+			return
+		}
+		handleReminderListComponent(ctx, bot, store, interaction, page)
+	case strings.HasPrefix(customID, ComponentSnoozeDeliveredReminderPrefix):
+		handleSnoozeComponent(ctx, bot, store, interaction, strings.TrimPrefix(customID, ComponentSnoozeDeliveredReminderPrefix))
+	case customID == ComponentSnoozeDeliveredReminderCustom:
+		language := getLanguage(ctx, store, getInteractionUserID(interaction), interaction.GuildID)
+		err := bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: ModalSnoozeDeliveredReminder,
+				Title:    language.Translate("Snooze reminder"),
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.TextInput{
+								CustomID:    ModalSnoozeDeliveredReminderTime,
+								Label:       language.Translate("Until when should the reminder be snoozed?"),
+								Style:       discordgo.TextInputShort,
+								Placeholder: language.Translate("e.g. 30m, 2h, at 15:30, tomorrow at 8am or friday 9am"),
+								Required:    true,
+								MaxLength:   64,
+							},
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "[discord][handleComponentInteraction] Failed to open snooze modal", "error", err)
+		}
+	}
+}
+
+func handleModalSubmitInteraction(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate) {
+	data := interaction.ModalSubmitData()
+	if data.CustomID != ModalSnoozeDeliveredReminder || interaction.Message == nil {
+		return
+	}
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rowComponent := range row.Components {
+			if input, ok := rowComponent.(*discordgo.TextInput); ok && input.CustomID == ModalSnoozeDeliveredReminderTime {
+				handleSnoozeComponent(ctx, bot, store, interaction, input.Value)
+				return
+			}
+		}
+	}
+}
+
+func handleReminderComponent(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate, customID string) {
+	language := getLanguage(ctx, store, getInteractionUserID(interaction), interaction.GuildID)
+	reminder, err := store.GetReminderByNotificationMessageID(ctx, interaction.Message.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][handleReminderComponent] Failed to retrieve reminder by notification message id", "error", err)
+		respondToInteraction(ctx, bot, interaction, language.Translate("Error: failed to retrieve reminder"))
+		return
+	}
+	if reminder == nil || reminder.CreatorID != getInteractionUserID(interaction) {
+		// The reminder no longer exists, so the buttons are no longer useful
+		updateInteractionMessage(ctx, bot, interaction, interaction.Message.Embeds, []discordgo.MessageComponent{})
+		return
+	}
+	localizeReminder(ctx, store, reminder)
+	switch customID {
+	case ComponentIncreaseReminderByHour:
+		reminder.Time = reminder.Time.Add(time.Hour)
+	case ComponentDecreaseReminderByHour:
+		// No need to worry about checking if we're under 0, just let it be naturally handled
+		reminder.Time = reminder.Time.Add(-time.Hour)
+	case ComponentIncreaseReminderByDay:
+		reminder.Time = reminder.Time.AddDate(0, 0, 1)
+	case ComponentDeleteReminder:
+		_ = bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+		deleteReminder(ctx, bot, store, interaction.ChannelID, reminder)
+		metrics.RemindersDeleted.Inc()
+		return
+	}
+	if customID != ComponentRefreshReminder {
+		if err = updateReminder(ctx, store, reminder); err != nil {
+			slog.ErrorContext(ctx, "[discord][handleReminderComponent] Failed to update reminder", "error", err)
+			respondToInteraction(ctx, bot, interaction, language.Translate("Error: failed to update reminder"))
+			return
+		}
+	}
+	embed := generateMessageEmbed("", reminder.GenerateNotificationMessageContent(language), embedColor)
+	updateInteractionMessage(ctx, bot, interaction, []*discordgo.MessageEmbed{embed}, generateNotificationMessageComponents(language))
+}
+
+func handleReminderListComponent(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate, page int) {
+	userID := getInteractionUserID(interaction)
+	directMessageChannelID := interaction.ChannelID
+	if len(interaction.GuildID) > 0 {
+		// The links of the list point to the notification messages, which are in the user's direct message channel
+		directMessageChannel, err := bot.UserChannelCreate(userID)
+		if err != nil {
+			slog.ErrorContext(ctx, "[discord][handleReminderListComponent] Failed to open direct message", "error", err)
+			return
+		}
+		directMessageChannelID = directMessageChannel.ID
+	}
+	language := getLanguage(ctx, store, userID, interaction.GuildID)
+	embed, numberOfPages, err := createReminderListMessageEmbed(ctx, store, language, directMessageChannelID, userID, page)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][handleReminderListComponent] Failed to create embed message", "error", err)
+		return
+	}
+	updateInteractionMessage(ctx, bot, interaction, []*discordgo.MessageEmbed{embed}, generateReminderListComponents(language, page, numberOfPages))
+}
+
+// handleSnoozeComponent re-creates a delivered reminder at the time described by the time expression passed
+func handleSnoozeComponent(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate, expression string) {
+	language := getLanguage(ctx, store, getInteractionUserID(interaction), interaction.GuildID)
+	deliveredReminder, err := store.GetDeliveredReminderByMessageID(ctx, interaction.Message.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][handleSnoozeComponent] Failed to retrieve delivered reminder", "error", err)
+		respondToInteraction(ctx, bot, interaction, language.Translate("Error: failed to retrieve reminder"))
+		return
+	}
+	embeds := interaction.Message.Embeds
+	if deliveredReminder == nil || deliveredReminder.UserID != getInteractionUserID(interaction) || time.Since(deliveredReminder.DeliveredAt) > limits.DeliveredReminderRetention {
+		if len(embeds) > 0 {
+			embeds[0].Footer = &discordgo.MessageEmbedFooter{Text: language.Translate("This reminder can no longer be snoozed")}
+		}
+		updateInteractionMessage(ctx, bot, interaction, embeds, []discordgo.MessageComponent{})
+		return
+	}
+	when, rest, err := parseReminderTime(ctx, store, deliveredReminder.UserID, expression)
+	if err == nil && len(strings.TrimSpace(rest)) > 0 {
+		err = i18n.Errorf("Unexpected text after the time: `%s`", strings.TrimSpace(rest))
+	}
+	if err != nil {
+		respondToInteraction(ctx, bot, interaction, language.Error(err))
+		return
+	}
+	reminder := &core.Reminder{
+		CreatorID:   deliveredReminder.UserID,
+		UserID:      deliveredReminder.UserID,
+		ChannelID:   deliveredReminder.ChannelID,
+		MessageLink: deliveredReminder.MessageLink,
+		Note:        deliveredReminder.Note,
+		Time:        when,
+
+		MessageSnapshot: deliveredReminder.MessageSnapshot,
+	}
+	if err = createReminder(ctx, bot, store, interaction.GuildID, metrics.SourceSnooze, reminder); err != nil {
+		slog.ErrorContext(ctx, "[discord][handleSnoozeComponent] Failed to create reminder", "error", err)
+		respondToInteraction(ctx, bot, interaction, language.Error(err))
+		return
+	}
+	_ = store.DeleteDeliveredReminderByMessageID(ctx, deliveredReminder.MessageID)
+	if len(embeds) > 0 {
+		// The layout is translated too, since time.Format only knows the English names of days and months
+		embeds[0].Footer = &discordgo.MessageEmbedFooter{Text: language.Sprintf("Snoozed until %s", when.Format(language.Translate("Monday, January 2 at 15:04 MST")))}
+	}
+	updateInteractionMessage(ctx, bot, interaction, embeds, []discordgo.MessageComponent{})
+}
+
+// purgeExpiredDeliveredReminders periodically deletes delivered reminders that can no longer be snoozed
+func purgeExpiredDeliveredReminders(ctx context.Context, store database.Store) {
+	for {
+		ctx := logging.WithCorrelationID(ctx)
+		if err := store.DeleteDeliveredRemindersDeliveredBefore(ctx, time.Now().Add(-limits.DeliveredReminderRetention)); err != nil {
+			slog.ErrorContext(ctx, "[discord][purgeExpiredDeliveredReminders] Failed to delete expired delivered reminders", "error", err)
+		}
+		select {
+		case <-time.After(time.Hour):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// updateInteractionMessage responds to a component interaction by updating the message the component is attached to
+func updateInteractionMessage(ctx context.Context, bot Client, interaction *discordgo.InteractionCreate, embeds []*discordgo.MessageEmbed, components []discordgo.MessageComponent) {
+	err := bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    interaction.Message.Content,
+			Embeds:     embeds,
+			Components: components,
+		},
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "[discord][updateInteractionMessage] Failed to respond to interaction", "error", err)
+	}
+}
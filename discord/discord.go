@@ -1,28 +1,32 @@
 package discord
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"strconv"
+	"log/slog"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/TwiN/discord-reminder-bot/config"
 	"github.com/TwiN/discord-reminder-bot/core"
 	"github.com/TwiN/discord-reminder-bot/database"
 	"github.com/TwiN/discord-reminder-bot/format"
+	"github.com/TwiN/discord-reminder-bot/i18n"
+	"github.com/TwiN/discord-reminder-bot/logging"
+	"github.com/TwiN/discord-reminder-bot/metrics"
 	"github.com/bwmarrin/discordgo"
 )
 
 const (
-	EmojiCreateReminder     = "⏰"
-	EmojiCreateReminderAlt1 = "⏲️"
-	EmojiCreateReminderAlt2 = "🎗️️"
-
+	// The following emojis are only used to manage reminders whose notification message was sent before buttons
+	// were supported
 	EmojiRefreshDuration  = "🔄"
 	EmojiIncreaseDuration = "🔼"
 	EmojiDecreaseDuration = "🔽"
 	EmojiDeleteReminder   = "🗑️"
 
+	// The following emojis are only used to navigate lists of reminders sent before buttons were supported
 	EmojiPageOne   = "1️⃣"
 	EmojiPageTwo   = "2️⃣"
 	EmojiPageThree = "3️⃣"
@@ -34,53 +38,145 @@ const (
 )
 
 const (
-	MaximumNoteLength               = 240
-	MaximumNumberOfRemindersPerUser = 35
+	// InvalidRepeatClauseMessageFormat is the message shown to a user whose repeat clause could not be parsed, in
+	// English. It must be translated with i18n.Language.Sprintf.
+	InvalidRepeatClauseMessageFormat = "Invalid repeat interval: ```%s```\n:information_source: _Try something like `every 24h`, `every 7d until 2030-12-31` or `every 1d 10 times`._"
+)
 
-	MinimumReminderDuration = time.Minute
-	MaximumReminderDuration = 1825 * 24 * time.Hour
+const (
+	// MaximumCommandPrefixLength is the maximum length of the command prefix a guild can configure
+	MaximumCommandPrefixLength = 8
 
-	ReminderListPageSize = 7
+	// SchedulerRefreshInterval is the interval at which the scheduler reloads reminders from the database, in case
+	// the database was modified without the scheduler being notified
+	SchedulerRefreshInterval = 30 * time.Minute
+	// MaximumConcurrentDeliveries is the maximum number of reminders delivered in parallel
+	MaximumConcurrentDeliveries = 10
 )
 
+var channelLinkRegex = regexp.MustCompile(`^https://(?:(?:ptb|canary)\.)?discord(?:app)?\.com/channels/(@me|\d+)/(\d+)(?:/(\d+))?$`)
+
 var (
 	botMention       string
 	botAvatar        string
 	botCommandPrefix string
+
+	// deliveryFallbackToOriginChannel is whether reminders that can't be sent by direct message should be delivered in
+	// the channel they were created from instead
+	deliveryFallbackToOriginChannel bool
+
+	// limits are the limits and defaults applied to reminders
+	limits config.Limits
+
+	// reminderEmojis are the emojis that users can react to a message with to be reminded about it
+	reminderEmojis []string
+
+	// embedColor is the color of the embeds sent by the bot
+	embedColor int
+
+	reminderScheduler *scheduler
 )
 
-func Start(bot *discordgo.Session, cfg *config.Config) {
-	botMention = "<@!" + bot.State.User.ID + ">"
-	botAvatar = bot.State.User.AvatarURL("64")
-	botCommandPrefix = cfg.CommandPrefix
-	bot.AddHandler(HandleMessage)
-	bot.AddHandler(HandleReactionAdd)
-	bot.AddHandler(HandleReactionRemove)
-	bot.AddHandler(HandleJoin)
+// Start registers the handlers of the bot and starts delivering the reminders persisted in store until ctx is cancelled.
+// Returns a channel that is closed once the worker has stopped, after delivering the reminders it was delivering when
+// ctx was cancelled.
+func Start(ctx context.Context, session *discordgo.Session, cfg *config.Config, store database.Store) <-chan struct{} {
+	bot := NewSessionClient(session)
+	initialize(bot, cfg, store)
+	session.AddHandler(func(_ *discordgo.Session, message *discordgo.MessageCreate) {
+		HandleMessage(bot, store, message)
+	})
+	session.AddHandler(func(_ *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
+		HandleReactionAdd(bot, store, reaction)
+	})
+	session.AddHandler(func(_ *discordgo.Session, reaction *discordgo.MessageReactionRemove) {
+		HandleReactionRemove(bot, store, reaction)
+	})
+	session.AddHandler(HandleJoin)
+	session.AddHandler(func(_ *discordgo.Session, interaction *discordgo.InteractionCreate) {
+		HandleInteraction(bot, store, interaction)
+	})
+	session.AddHandler(func(_ *discordgo.Session, _ *discordgo.Connect) {
+		setGatewayConnected(true)
+	})
+	session.AddHandler(func(_ *discordgo.Session, _ *discordgo.Disconnect) {
+		setGatewayConnected(false)
+	})
+	registerApplicationCommands(bot)
 	_ = bot.UpdateListeningStatus(botCommandPrefix + "RemindMe")
-	go worker(bot)
+	markStarted()
+	stopped := make(chan struct{})
+	go func() {
+		worker(ctx, bot, store)
+		close(stopped)
+	}()
+	go purgeExpiredDeliveredReminders(ctx, store)
+	return stopped
+}
+
+// initialize prepares the bot to handle events and deliver the reminders persisted in store, without registering any
+// handler or starting any goroutine
+func initialize(bot Client, cfg *config.Config, store database.Store) {
+	botMention = "<@!" + bot.BotUser().ID + ">"
+	botAvatar = bot.BotUser().AvatarURL("64")
+	botCommandPrefix = cfg.CommandPrefix
+	deliveryFallbackToOriginChannel = cfg.DeliveryFallbackToOriginChannel
+	limits = cfg.Limits
+	reminderEmojis = cfg.ReminderEmojis
+	embedColor = cfg.EmbedColor
+	reminderScheduler = newScheduler(limits.SchedulerCapacity, MaximumConcurrentDeliveries, SchedulerRefreshInterval, func(limit int) ([]*core.Reminder, error) {
+		return store.GetUpcomingReminders(logging.WithCorrelationID(context.Background()), limit)
+	}, func(notificationMessageID string) {
+		deliverReminder(bot, store, notificationMessageID)
+	})
 }
 
-func HandleJoin(bot *discordgo.Session, guild *discordgo.GuildCreate) {
-	log.Printf("[HandleJoin] Connected to %s (%d members)", guild.Name, guild.MemberCount)
+func HandleJoin(bot Client, guild *discordgo.GuildCreate) {
+	slog.Info("[discord][HandleJoin] Connected to guild", "guild_id", guild.ID, "guild_name", guild.Name, "members", guild.MemberCount)
 }
 
-// sendDirectMessage sends a direct message to a user and returns the ID of the message sent
-func sendDirectMessage(bot *discordgo.Session, userID string, title, description string) (*discordgo.Message, error) {
+// sendDirectMessage sends a direct message to a user and returns the message sent
+// components may be nil if the message should not have any components
+func sendDirectMessage(bot Client, userID string, title, description string, components []discordgo.MessageComponent) (*discordgo.Message, error) {
 	directMessageChannel, err := bot.UserChannelCreate(userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create DM with %s: %s", userID, err.Error())
+		return nil, fmt.Errorf("failed to create DM with %s: %w", userID, err)
 	}
-	embed := generateMessageEmbed(title, description, 0x20B020)
-	message, err := bot.ChannelMessageSendEmbed(directMessageChannel.ID, embed)
+	embed := generateMessageEmbed(title, description, embedColor)
+	message, err := bot.ChannelMessageSendComplex(directMessageChannel.ID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: components,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send DM to %s: %s", userID, err.Error())
+		return nil, fmt.Errorf("failed to send DM to %s: %w", userID, err)
 	}
 	return message, nil
 }
 
-func updateExistingMessage(bot *discordgo.Session, channelID, messageID, title, description string) (*discordgo.Message, error) {
-	embed := generateMessageEmbed(title, description, 0x20B020)
+// sendChannelMessage sends a message mentioning a user or, if roleID is not empty, a role in a channel and returns the
+// message sent
+// components may be nil if the message should not have any components
+func sendChannelMessage(bot Client, channelID, userID, roleID string, title, description string, components []discordgo.MessageComponent) (*discordgo.Message, error) {
+	embed := generateMessageEmbed(title, description, embedColor)
+	// Only the recipient should be mentioned, even if the note mentions other users or roles
+	content, allowedMentions, recipient := "<@"+userID+">", &discordgo.MessageAllowedMentions{Users: []string{userID}}, userID
+	if len(roleID) > 0 {
+		content, allowedMentions, recipient = "<@&"+roleID+">", &discordgo.MessageAllowedMentions{Roles: []string{roleID}}, roleID
+	}
+	message, err := bot.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         content,
+		Embeds:          []*discordgo.MessageEmbed{embed},
+		Components:      components,
+		AllowedMentions: allowedMentions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message to %s in channel %s: %w", recipient, channelID, err)
+	}
+	return message, nil
+}
+
+func updateExistingMessage(bot Client, channelID, messageID, title, description string) (*discordgo.Message, error) {
+	embed := generateMessageEmbed(title, description, embedColor)
 	message, err := bot.ChannelMessageEditEmbed(channelID, messageID, embed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update message with ID %s in channel %s: %s", messageID, channelID, err.Error())
@@ -88,77 +184,291 @@ func updateExistingMessage(bot *discordgo.Session, channelID, messageID, title,
 	return message, nil
 }
 
-func createReminder(bot *discordgo.Session, userID, guildID, channelID, messageID, note string, when time.Time) (*core.Reminder, error) {
-	if len(note) > MaximumNoteLength {
-		return nil, fmt.Errorf("note exceeded maximum length of %d", MaximumNoteLength)
+// generateChannelLink generates a link to a channel
+// If guildID is empty, the channel is assumed to be a direct message channel
+func generateChannelLink(guildID, channelID string) string {
+	if len(guildID) == 0 {
+		guildID = "@me"
 	}
-	numberOfReminders, _ := database.CountRemindersByUserID(userID)
-	if numberOfReminders >= MaximumNumberOfRemindersPerUser {
-		return nil, fmt.Errorf("you have reached the maximum number of reminders a single user can have (%d)", MaximumNumberOfRemindersPerUser)
+	return fmt.Sprintf("https://discord.com/channels/%s/%s", guildID, channelID)
+}
+
+// getChannelIDFromLink returns the ID of the channel that a link to a channel or a message points to, or an empty
+// string if the link doesn't point to a channel of a guild
+func getChannelIDFromLink(link string) string {
+	match := channelLinkRegex.FindStringSubmatch(link)
+	if match == nil || match[1] == "@me" {
+		return ""
 	}
+	return match[2]
+}
+
+// generateMessageLink generates a link to a message
+// If guildID is empty, the message is assumed to be in a direct message channel
+func generateMessageLink(guildID, channelID, messageID string) string {
+	return generateChannelLink(guildID, channelID) + "/" + messageID
+}
+
+// validateDeliveryChannel makes sure that both the user and the bot can post in a channel of the guild
+func validateDeliveryChannel(ctx context.Context, bot Client, store database.Store, guildID, userID, channelID string) error {
 	if len(guildID) == 0 {
-		guildID = "@me"
+		return i18n.Errorf("Reminders can only be delivered in a channel when they are created from a server")
 	}
-	reminder := &core.Reminder{
-		UserID:      userID,
-		MessageLink: fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, messageID),
-		Note:        note,
-		Time:        when,
+	guildSettings := getGuildSettings(ctx, store, guildID)
+	if !guildSettings.AllowsInChannelDelivery() {
+		return i18n.Errorf("Reminders created from this server can only be delivered by direct message")
 	}
-	directMessage, err := sendDirectMessage(bot, userID, "", reminder.GenerateNotificationMessageContent())
+	channel, err := bot.Channel(channelID)
 	if err != nil {
-		return nil, err
+		return i18n.Errorf("Channel <#%s> does not exist or is not visible to me", channelID)
+	}
+	if channel.GuildID != guildID {
+		return i18n.Errorf("Channel <#%s> must be in this server", channelID)
+	}
+	if !guildSettings.AllowsChannel(channelID) && !(channel.IsThread() && guildSettings.AllowsChannel(channel.ParentID)) {
+		return i18n.Errorf("I cannot be used in <#%s>", channelID)
+	}
+	requiredPermissions := int64(discordgo.PermissionViewChannel | discordgo.PermissionSendMessages)
+	if channel.IsThread() {
+		requiredPermissions = discordgo.PermissionViewChannel | discordgo.PermissionSendMessagesInThreads
+	}
+	permissionChannelID := getPermissionChannelID(channel)
+	if permissions, err := bot.UserChannelPermissions(userID, permissionChannelID); err != nil || permissions&requiredPermissions != requiredPermissions {
+		return i18n.Errorf("You are not allowed to send messages in <#%s>", channelID)
+	}
+	if permissions, err := bot.UserChannelPermissions(bot.BotUser().ID, permissionChannelID); err != nil || permissions&requiredPermissions != requiredPermissions {
+		return i18n.Errorf("I am not allowed to send messages in <#%s>", channelID)
+	}
+	return nil
+}
+
+// getPermissionChannelID returns the ID of the channel to check permissions in, since threads inherit the permissions
+// of their parent channel
+func getPermissionChannelID(channel *discordgo.Channel) string {
+	if channel.IsThread() {
+		return channel.ParentID
+	}
+	return channel.ID
+}
+
+// validateMessageLink makes sure that a link points to a message that both the user and the bot can see
+func validateMessageLink(bot Client, userID, messageLink string) error {
+	match := messageLinkRegex.FindStringSubmatch(messageLink)
+	if match == nil {
+		return i18n.Errorf("Invalid message link. You can get the link of a message by right-clicking on it and selecting `Copy Message Link`.")
+	}
+	guildID, channelID, messageID := match[1], match[2], match[3]
+	if guildID == "@me" {
+		// The only direct messages the bot can see are those it exchanged with users, so the user must be the recipient
+		directMessageChannel, err := bot.UserChannelCreate(userID)
+		if err != nil || directMessageChannel.ID != channelID {
+			return i18n.Errorf("The only direct messages I can remind you about are the ones we exchanged")
+		}
+	} else {
+		channel, err := bot.Channel(channelID)
+		if err != nil || channel.GuildID != guildID {
+			return i18n.Errorf("The message linked does not exist or is not visible to me")
+		}
+		requiredPermissions := int64(discordgo.PermissionViewChannel | discordgo.PermissionReadMessageHistory)
+		if permissions, err := bot.UserChannelPermissions(userID, getPermissionChannelID(channel)); err != nil || permissions&requiredPermissions != requiredPermissions {
+			return i18n.Errorf("You are not allowed to see the message linked")
+		}
+	}
+	if _, err := bot.ChannelMessage(channelID, messageID); err != nil {
+		return i18n.Errorf("The message linked does not exist or is not visible to me")
+	}
+	return nil
+}
+
+// createReminder sends a notification message to the creator of a validated reminder and persists the reminder
+func createReminder(ctx context.Context, bot Client, store database.Store, guildID, source string, reminder *core.Reminder) error {
+	if len(reminder.Note) > limits.MaximumNoteLength {
+		return i18n.Errorf("Note must have less than %d characters", limits.MaximumNoteLength)
+	}
+	// The guild may have configured a maximum before the highest configurable maximum was lowered
+	maximumNumberOfReminders := min(getGuildSettings(ctx, store, guildID).GetMaximumRemindersPerUser(limits.MaximumNumberOfRemindersPerUser), limits.MaximumConfigurableRemindersPerUser)
+	numberOfReminders, _ := store.CountRemindersByCreatorID(ctx, reminder.CreatorID)
+	if numberOfReminders >= maximumNumberOfReminders {
+		return i18n.Errorf("You have reached the maximum number of reminders a single user can have (%d)", maximumNumberOfReminders)
+	}
+	language := getReminderLanguage(ctx, store, reminder.CreatorID, reminder)
+	directMessage, err := sendDirectMessage(bot, reminder.CreatorID, "", reminder.GenerateNotificationMessageContent(language), generateNotificationMessageComponents(language))
+	if err != nil {
+		return err
 	}
 	reminder.NotificationMessageID = directMessage.ID
-	err = database.CreateReminder(reminder)
+	if reminder.MessageSnapshot == nil {
+		reminder.MessageSnapshot = captureMessageSnapshot(ctx, bot, store, reminder.MessageLink)
+	}
+	err = store.CreateReminder(ctx, reminder)
+	if err != nil {
+		return fmt.Errorf("failed to create reminder in database: %s", err.Error())
+	}
+	reminderScheduler.Schedule(reminder.NotificationMessageID, reminder.Time)
+	metrics.RemindersCreated.WithLabelValues(source).Inc()
+	return nil
+}
+
+// captureMessageSnapshot takes a snapshot of the message that a link points to, or returns nil if the link doesn't point
+// to a message, if the message can't be retrieved, or if the message is a command sent to the bot
+func captureMessageSnapshot(ctx context.Context, bot Client, store database.Store, messageLink string) *core.MessageSnapshot {
+	match := channelLinkRegex.FindStringSubmatch(messageLink)
+	if match == nil || len(match[3]) == 0 {
+		return nil
+	}
+	message, err := bot.ChannelMessage(match[2], match[3])
+	if err != nil {
+		slog.WarnContext(ctx, "[discord][captureMessageSnapshot] Failed to retrieve message", "message_id", match[3], "channel_id", match[2], "error", err)
+		return nil
+	}
+	var guildID string
+	if match[1] != "@me" {
+		guildID = match[1]
+	}
+	if message.Author == nil || strings.HasPrefix(message.Content, getCommandPrefix(ctx, store, guildID)) || strings.HasPrefix(message.Content, botMention) {
+		// Quoting the command used to create the reminder would not be helpful
+		return nil
+	}
+	var channelName string
+	if channel, err := bot.Channel(message.ChannelID); err == nil {
+		channelName = channel.Name
+	}
+	var attachmentURLs []string
+	for _, attachment := range message.Attachments {
+		attachmentURLs = append(attachmentURLs, attachment.URL)
+	}
+	authorName := message.Author.GlobalName
+	if len(authorName) == 0 {
+		authorName = message.Author.Username
+	}
+	return core.NewMessageSnapshot(authorName, channelName, message.Content, attachmentURLs)
+}
+
+func deleteReminder(ctx context.Context, bot Client, store database.Store, directMessageChannelID string, reminder *core.Reminder) {
+	embed := generateMessageEmbed("", "~~"+reminder.GenerateNotificationMessageContent(getReminderLanguage(ctx, store, reminder.CreatorID, reminder))+"~~", embedColor)
+	notificationMessage, err := bot.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    directMessageChannelID,
+		ID:         reminder.NotificationMessageID,
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &[]discordgo.MessageComponent{},
+	})
+	if err == nil && len(notificationMessage.Reactions) > 0 {
+		// Notification messages sent before buttons were supported are managed with reactions instead
+		_ = bot.MessageReactionRemove(directMessageChannelID, reminder.NotificationMessageID, EmojiRefreshDuration, "@me")
+		_ = bot.MessageReactionRemove(directMessageChannelID, reminder.NotificationMessageID, EmojiIncreaseDuration, "@me")
+		_ = bot.MessageReactionRemove(directMessageChannelID, reminder.NotificationMessageID, EmojiDecreaseDuration, "@me")
+		_ = bot.MessageReactionRemove(directMessageChannelID, reminder.NotificationMessageID, EmojiDeleteReminder, "@me")
+	}
+	_ = store.DeleteReminderByNotificationMessageID(ctx, reminder.NotificationMessageID)
+	reminderScheduler.Unschedule(reminder.NotificationMessageID)
+}
+
+// getUserLocation returns the location of the timezone configured by a user, or time.Local if the user has not
+// configured a timezone
+func getUserLocation(ctx context.Context, store database.Store, userID string) *time.Location {
+	settings, err := store.GetUserSettings(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create reminder in database: %s", err.Error())
+		slog.ErrorContext(ctx, "[discord][getUserLocation] Failed to retrieve user settings", "user_id", userID, "error", err)
 	}
-	_ = bot.MessageReactionAdd(directMessage.ChannelID, directMessage.ID, EmojiRefreshDuration)
-	_ = bot.MessageReactionAdd(directMessage.ChannelID, directMessage.ID, EmojiIncreaseDuration)
-	_ = bot.MessageReactionAdd(directMessage.ChannelID, directMessage.ID, EmojiDecreaseDuration)
-	_ = bot.MessageReactionAdd(directMessage.ChannelID, directMessage.ID, EmojiDeleteReminder)
-	return reminder, nil
+	return settings.Location()
 }
 
-func deleteReminder(bot *discordgo.Session, directMessageChannelID string, reminder *core.Reminder) {
-	_, _ = updateExistingMessage(bot, directMessageChannelID, reminder.NotificationMessageID, "", "~~"+reminder.GenerateNotificationMessageContent()+"~~")
-	_ = bot.MessageReactionRemove(directMessageChannelID, reminder.NotificationMessageID, EmojiRefreshDuration, "@me")
-	_ = bot.MessageReactionRemove(directMessageChannelID, reminder.NotificationMessageID, EmojiIncreaseDuration, "@me")
-	_ = bot.MessageReactionRemove(directMessageChannelID, reminder.NotificationMessageID, EmojiDecreaseDuration, "@me")
-	_ = bot.MessageReactionRemove(directMessageChannelID, reminder.NotificationMessageID, EmojiDeleteReminder, "@me")
-	_ = database.DeleteReminderByNotificationMessageID(reminder.NotificationMessageID)
+// getLanguage returns the language selected by a user or, if the user has not selected any, the language selected by
+// a guild. guildID may be empty, in which case the default language is used as fallback.
+func getLanguage(ctx context.Context, store database.Store, userID, guildID string) i18n.Language {
+	settings, err := store.GetUserSettings(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][getLanguage] Failed to retrieve user settings", "user_id", userID, "error", err)
+	}
+	if language, ok := i18n.ParseLanguage(settings.GetLanguage()); ok {
+		return language
+	}
+	if language, ok := i18n.ParseLanguage(getGuildSettings(ctx, store, guildID).GetLanguage()); ok {
+		return language
+	}
+	return i18n.DefaultLanguage
+}
+
+// getGuildSettings returns the settings of a guild, or nil if guildID is empty or if the guild has never been configured.
+// Every method of core.GuildSettings can be called on nil settings, in which case the defaults are used.
+func getGuildSettings(ctx context.Context, store database.Store, guildID string) *core.GuildSettings {
+	if len(guildID) == 0 {
+		return nil
+	}
+	settings, err := store.GetGuildSettings(ctx, guildID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][getGuildSettings] Failed to retrieve guild settings", "guild_id", guildID, "error", err)
+	}
+	return settings
+}
+
+// getCommandPrefix returns the prefix of the bot's commands in a guild, or the default prefix if guildID is empty
+func getCommandPrefix(ctx context.Context, store database.Store, guildID string) string {
+	return getGuildSettings(ctx, store, guildID).GetCommandPrefix(botCommandPrefix)
+}
+
+// isChannelAllowed returns whether the bot can be used in a channel of a guild with the settings passed as parameter.
+// Threads are allowed if their parent channel is.
+func isChannelAllowed(bot Client, guildSettings *core.GuildSettings, channelID string) bool {
+	if guildSettings.AllowsChannel(channelID) {
+		return true
+	}
+	channel, err := bot.Channel(channelID)
+	return err == nil && channel.IsThread() && guildSettings.AllowsChannel(channel.ParentID)
+}
+
+// getReminderLanguage returns the language in which to write to a user about a reminder, using the guild that the
+// reminder was created from as fallback
+func getReminderLanguage(ctx context.Context, store database.Store, userID string, reminder *core.Reminder) i18n.Language {
+	var guildID string
+	if match := channelLinkRegex.FindStringSubmatch(reminder.MessageLink); match != nil && match[1] != "@me" {
+		guildID = match[1]
+	}
+	return getLanguage(ctx, store, userID, guildID)
+}
+
+// localizeReminder converts the times of a reminder to the timezone configured by the reminder's creator
+func localizeReminder(ctx context.Context, store database.Store, reminder *core.Reminder) {
+	location := getUserLocation(ctx, store, reminder.CreatorID)
+	reminder.Time = reminder.Time.In(location)
+	if !reminder.RepeatUntil.IsZero() {
+		reminder.RepeatUntil = reminder.RepeatUntil.In(location)
+	}
 }
 
 // createReminderListMessageEmbed creates a MessageEmbed and returns the total number of pages
 // for the number of reminders that the user has
-func createReminderListMessageEmbed(notificationMessageChannelID, userID string, page int) (*discordgo.MessageEmbed, int, error) {
-	reminders, err := database.GetRemindersByUserID(userID, page-1, ReminderListPageSize)
+func createReminderListMessageEmbed(ctx context.Context, store database.Store, language i18n.Language, notificationMessageChannelID, userID string, page int) (*discordgo.MessageEmbed, int, error) {
+	reminders, err := store.GetRemindersByCreatorID(ctx, userID, page-1, limits.ReminderListPageSize)
 	if err != nil {
 		return nil, 0, err
 	}
 	var fields []*discordgo.MessageEmbedField
 	for _, reminder := range reminders {
+		name := language.Sprintf("#%d · In %s from now", reminder.ID, format.PrettyDurationIn(language, time.Until(reminder.Time)))
+		if reminder.IsRepeating() {
+			name += language.Sprintf(" (repeats every %s)", format.PrettyPeriodIn(language, reminder.RepeatPeriod()))
+		}
 		fields = append(fields, &discordgo.MessageEmbedField{
-			Name:  fmt.Sprintf("In %s from now", format.PrettyDuration(time.Until(reminder.Time))),
-			Value: reminder.GenerateReminderMessageContentInList(notificationMessageChannelID),
+			Name:  name,
+			Value: reminder.GenerateReminderMessageContentInList(language, notificationMessageChannelID),
 		})
 	}
 	var description string
 	if len(fields) == 0 {
-		description = "_No reminders to display_"
+		description = language.Translate("_No reminders to display_")
 	} else {
-		description = "Below is a list of all reminders on page " + strconv.Itoa(page)
+		description = language.Sprintf("Below is a list of all reminders on page %d", page)
 	}
-	numberOfReminders, _ := database.CountRemindersByUserID(userID)
-	numberOfPages := numberOfReminders / ReminderListPageSize
-	if numberOfPages == 0 || numberOfReminders%ReminderListPageSize != 0 {
+	numberOfReminders, _ := store.CountRemindersByCreatorID(ctx, userID)
+	numberOfPages := numberOfReminders / limits.ReminderListPageSize
+	if numberOfPages == 0 || numberOfReminders%limits.ReminderListPageSize != 0 {
 		numberOfPages += 1
 	}
-	embed := generateMessageEmbed("List of Reminders", description, 0x20B020)
+	embed := generateMessageEmbed(language.Translate("List of Reminders"), description, embedColor)
 	embed.Fields = fields
 	embed.Footer = &discordgo.MessageEmbedFooter{
-		Text:    fmt.Sprintf("Page %d out of %d", page, numberOfPages),
+		Text:    language.Sprintf("Page %d out of %d", page, numberOfPages),
 		IconURL: botAvatar,
 	}
 	return embed, numberOfPages, nil
@@ -0,0 +1,416 @@
+package discord
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/config"
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/database"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestGetChannelIDFromLink(t *testing.T) {
+	scenarios := []struct {
+		Name              string
+		Link              string
+		ExpectedChannelID string
+	}{
+		{
+			Name:              "message-link",
+			Link:              "https://discord.com/channels/1/2/3",
+			ExpectedChannelID: "2",
+		},
+		{
+			Name:              "channel-link",
+			Link:              "https://discord.com/channels/1/2",
+			ExpectedChannelID: "2",
+		},
+		{
+			Name:              "legacy-domain",
+			Link:              "https://discordapp.com/channels/1/2/3",
+			ExpectedChannelID: "2",
+		},
+		{
+			Name:              "direct-message-link",
+			Link:              "https://discord.com/channels/@me/2/3",
+			ExpectedChannelID: "",
+		},
+		{
+			Name:              "invalid-link",
+			Link:              "https://example.com/channels/1/2/3",
+			ExpectedChannelID: "",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if channelID := getChannelIDFromLink(scenario.Link); channelID != scenario.ExpectedChannelID {
+				t.Errorf("expected channel ID '%s', got '%s'", scenario.ExpectedChannelID, channelID)
+			}
+		})
+	}
+}
+
+// newFakeBot prepares the bot to handle events sent through a FakeClient, with everything kept in memory
+func newFakeBot(t *testing.T) (*FakeClient, *database.MemoryStore) {
+	return newFakeBotWithConfig(t, config.Default())
+}
+
+// newFakeBotWithConfig is newFakeBot with a configuration other than the default one
+func newFakeBotWithConfig(t *testing.T, cfg *config.Config) (*FakeClient, *database.MemoryStore) {
+	store := database.NewMemoryStore()
+	bot := NewFakeClient("1", store)
+	initialize(bot, cfg, store)
+	return bot, store
+}
+
+func TestHandleMessage_RemindMe(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	directMessageChannelID := bot.DirectMessageChannelID(user.ID)
+	message := bot.InjectMessage("", directMessageChannelID, user, "!remindme 2h water the plants")
+	if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != EmojiSuccess {
+		t.Fatal("message should've been reacted to with", EmojiSuccess, "got", reactions)
+	}
+	reminders, _ := store.GetRemindersByCreatorID(context.Background(), user.ID, 0, 10)
+	if len(reminders) != 1 {
+		t.Fatal("user should've had 1 reminder, got", len(reminders))
+	}
+	if reminders[0].Note != "water the plants" || time.Until(reminders[0].Time) < 119*time.Minute || time.Until(reminders[0].Time) > 2*time.Hour {
+		t.Error("reminder should've had the note and the time requested, got", reminders[0].Note, reminders[0].Time)
+	}
+	if reminders[0].MessageSnapshot != nil {
+		t.Error("reminder should not have had a snapshot of the command used to create it, got", reminders[0].MessageSnapshot)
+	}
+	notificationMessage := bot.Message(reminders[0].NotificationMessageID)
+	if notificationMessage == nil || notificationMessage.ChannelID != directMessageChannelID || !strings.Contains(notificationMessage.Embeds[0].Description, "I will remind you") {
+		t.Error("notification message should've been sent to the user by direct message, got", notificationMessage)
+	}
+}
+
+func TestHandleMessage_RemindMeWithConfiguredLimits(t *testing.T) {
+	cfg := config.Default()
+	cfg.Limits.MaximumNoteLength = 10
+	cfg.Limits.MaximumReminderDuration = 24 * time.Hour
+	bot, store := newFakeBotWithConfig(t, cfg)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	directMessageChannelID := bot.DirectMessageChannelID(user.ID)
+	scenarios := []struct {
+		Name           string
+		Command        string
+		ExpectedResult string
+	}{
+		{
+			Name:           "note-within-limit",
+			Command:        "!remindme 2h plants",
+			ExpectedResult: EmojiSuccess,
+		},
+		{
+			Name:           "note-too-long",
+			Command:        "!remindme 2h water the plants",
+			ExpectedResult: EmojiError,
+		},
+		{
+			Name:           "duration-too-long",
+			Command:        "!remindme 2d plants",
+			ExpectedResult: EmojiError,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			message := bot.InjectMessage("", directMessageChannelID, user, scenario.Command)
+			if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != scenario.ExpectedResult {
+				t.Error("message should've been reacted to with", scenario.ExpectedResult, "got", reactions)
+			}
+		})
+	}
+	if numberOfReminders, _ := store.CountReminders(context.Background()); numberOfReminders != 1 {
+		t.Error("only the reminder within the limits should've been created, got", numberOfReminders)
+	}
+}
+
+func TestHandleMessage_RemindMeErrorIsLocalized(t *testing.T) {
+	cfg := config.Default()
+	cfg.Limits.MaximumNumberOfRemindersPerUser = 1
+	bot, _ := newFakeBotWithConfig(t, cfg)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	directMessageChannelID := bot.DirectMessageChannelID(user.ID)
+	bot.InjectMessage("", directMessageChannelID, user, "!language français")
+	bot.InjectMessage("", directMessageChannelID, user, "!remindme 2h water the plants")
+	message := bot.InjectMessage("", directMessageChannelID, user, "!remindme 3h water the plants")
+	if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != EmojiError {
+		t.Fatal("message should've been reacted to with", EmojiError, "got", reactions)
+	}
+	replies := bot.SentMessages(directMessageChannelID)
+	if reply := replies[len(replies)-1].Content; reply != "Vous avez atteint le nombre maximal de rappels par utilisateur (1)" {
+		t.Error("user should've been told in French that they reached the maximum number of reminders, got", reply)
+	}
+}
+
+func TestHandleMessage_RemindMeWithoutPermissionToRemindOthers(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user, otherUser := &discordgo.User{ID: "2", Username: "user"}, &discordgo.User{ID: "3", Username: "other-user"}
+	bot.AddChannel("10", "11")
+	bot.AddMember("10", user)
+	bot.AddMember("10", otherUser)
+	message := bot.InjectMessage("10", "11", user, "!remindme <@3> 2h water the plants")
+	if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != EmojiError {
+		t.Fatal("message should've been reacted to with", EmojiError, "got", reactions)
+	}
+	if replies := bot.SentMessages("11"); len(replies) != 1 || !strings.Contains(replies[0].Content, "not allowed") {
+		t.Error("user should've been told that they are not allowed to remind other users, got", replies)
+	}
+	if numberOfReminders, _ := store.CountReminders(context.Background()); numberOfReminders != 0 {
+		t.Error("no reminder should've been created, got", numberOfReminders)
+	}
+}
+
+func TestHandleMessage_RemindMeInReplyToMessage(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	bot.AddChannel("10", "11")
+	question := bot.InjectMessage("10", "11", &discordgo.User{ID: "3", Username: "teammate"}, "what's the status of the migration?")
+	command := &discordgo.Message{GuildID: "10", ChannelID: "11", Author: user, Content: "!remindme 2h", MessageReference: question.Reference()}
+	HandleMessage(bot, store, &discordgo.MessageCreate{Message: command})
+	reminders, _ := store.GetRemindersByCreatorID(context.Background(), user.ID, 0, 1)
+	if len(reminders) != 1 {
+		t.Fatal("reminder should've been created")
+	}
+	if reminders[0].MessageLink != generateMessageLink("10", "11", question.ID) {
+		t.Error("reminder should've linked to the message replied to, got", reminders[0].MessageLink)
+	}
+	if reminders[0].MessageSnapshot == nil || reminders[0].MessageSnapshot.Content != question.Content {
+		t.Error("reminder should've had a snapshot of the message replied to, got", reminders[0].MessageSnapshot)
+	}
+}
+
+func TestHandleMessage_RemindMeInReplyToMessageInOtherGuild(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	bot.AddChannel("10", "11")
+	bot.AddChannel("20", "21")
+	bot.SetPermissions(user.ID, "21", 0)
+	secret := bot.InjectMessage("20", "21", &discordgo.User{ID: "3", Username: "stranger"}, "the password is hunter2")
+	command := &discordgo.Message{GuildID: "10", ChannelID: "11", Author: user, Content: "!remindme 2h", MessageReference: secret.Reference()}
+	HandleMessage(bot, store, &discordgo.MessageCreate{Message: command})
+	if numberOfReminders, _ := store.CountReminders(context.Background()); numberOfReminders != 0 {
+		t.Error("no reminder should've been created for a message the user cannot see, got", numberOfReminders)
+	}
+}
+
+func TestHandleMessage_RemindMeWithMessageLink(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	bot.AddChannel("10", "11")
+	bot.AddChannel("10", "12")
+	bot.SetPermissions(user.ID, "12", 0)
+	question := bot.InjectMessage("10", "11", &discordgo.User{ID: "3", Username: "teammate"}, "what's the status of the migration?")
+	secret := bot.InjectMessage("10", "12", &discordgo.User{ID: "3", Username: "teammate"}, "the password is hunter2")
+	scenarios := []struct {
+		Name                string
+		Content             string
+		ExpectedMessageLink string
+	}{
+		{
+			Name:                "visible-message",
+			Content:             "!remindme 2h " + generateMessageLink("10", "11", question.ID) + " answer",
+			ExpectedMessageLink: generateMessageLink("10", "11", question.ID),
+		},
+		{
+			Name:                "message-in-channel-the-user-cannot-see",
+			Content:             "!remindme 2h " + generateMessageLink("10", "12", secret.ID),
+			ExpectedMessageLink: "",
+		},
+		{
+			Name:                "message-that-does-not-exist",
+			Content:             "!remindme 2h " + generateMessageLink("10", "11", "999999"),
+			ExpectedMessageLink: "",
+		},
+		{
+			Name:                "direct-message-with-someone-else",
+			Content:             "!remindme 2h " + generateMessageLink("", bot.DirectMessageChannelID("3"), question.ID),
+			ExpectedMessageLink: "",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			command := bot.InjectMessage("10", "11", user, scenario.Content)
+			reminders, _ := store.GetRemindersByCreatorID(context.Background(), user.ID, 0, 1)
+			if len(scenario.ExpectedMessageLink) == 0 {
+				if reactions := bot.Reactions(command.ID); len(reactions) != 1 || reactions[0] != EmojiError || len(reminders) != 0 {
+					t.Fatal("no reminder should've been created, got", reminders)
+				}
+				return
+			}
+			if len(reminders) != 1 || reminders[0].MessageLink != scenario.ExpectedMessageLink || reminders[0].Note != "answer" {
+				t.Fatal("reminder should've linked to", scenario.ExpectedMessageLink, "got", reminders)
+			}
+			_ = store.DeleteReminderByNotificationMessageID(context.Background(), reminders[0].NotificationMessageID)
+		})
+	}
+}
+
+func TestHandleMessage_Language(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user, admin, member := &discordgo.User{ID: "2", Username: "user"}, &discordgo.User{ID: "3", Username: "admin"}, &discordgo.User{ID: "4", Username: "member"}
+	bot.AddChannel("10", "11")
+	bot.AddMember("10", user)
+	bot.AddMember("10", admin)
+	bot.AddMember("10", member)
+	bot.SetPermissions(admin.ID, "11", FakeDefaultPermissions|discordgo.PermissionManageGuild)
+	// A user can select their own language
+	directMessageChannelID := bot.DirectMessageChannelID(user.ID)
+	bot.InjectMessage("", directMessageChannelID, user, "!language français")
+	if replies := bot.SentMessages(directMessageChannelID); len(replies) != 1 || replies[0].Content != "Je vous répondrai désormais en Français." {
+		t.Fatal("user should've been told in French that their language was changed, got", replies)
+	}
+	bot.InjectMessage("", directMessageChannelID, user, "!remindme 2h water the plants")
+	reminders, _ := store.GetRemindersByCreatorID(context.Background(), user.ID, 0, 1)
+	if len(reminders) != 1 {
+		t.Fatal("reminder should've been created")
+	}
+	if notificationMessage := bot.Message(reminders[0].NotificationMessageID); !strings.Contains(notificationMessage.Embeds[0].Description, "Je vous rappellerai") || !strings.Contains(notificationMessage.Embeds[0].Description, "dans 2 heures") {
+		t.Error("notification message should've been in French, got", notificationMessage.Embeds[0].Description)
+	}
+	// Only members who can manage the guild can select the language of the guild
+	message := bot.InjectMessage("10", "11", member, "!language server de")
+	if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != EmojiError {
+		t.Fatal("message should've been reacted to with", EmojiError, "got", reactions)
+	}
+	message = bot.InjectMessage("10", "11", admin, "!language server de")
+	if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != EmojiSuccess {
+		t.Fatal("message should've been reacted to with", EmojiSuccess, "got", reactions)
+	}
+	// The language of the guild is used for members who did not select a language, but not for those who did
+	bot.InjectMessage("10", "11", member, "!remindme soon")
+	bot.InjectMessage("10", "11", user, "!remindme soon")
+	replies := bot.SentMessages("11")
+	if len(replies) < 2 {
+		t.Fatal("both members should've been told that the time is invalid, got", replies)
+	}
+	if reply := replies[len(replies)-2].Content; !strings.HasPrefix(reply, "Ungültiges Zeitformat") || !strings.Contains(reply, "ungültige Dauer") {
+		t.Error("member without a language should've been answered in German, got", reply)
+	}
+	if reply := replies[len(replies)-1].Content; !strings.HasPrefix(reply, "Format d'heure invalide") {
+		t.Error("user should've been answered in French, got", reply)
+	}
+	// Resetting the language of the user makes the language of the guild apply to them
+	bot.InjectMessage("10", "11", user, "!language reset")
+	if reply := bot.SentMessages("11"); !strings.Contains(reply[len(reply)-1].Content, "Deutsch") {
+		t.Error("user should've been told that the bot now responds in German, got", reply[len(reply)-1].Content)
+	}
+}
+
+func TestHandleMessage_Config(t *testing.T) {
+	bot, store := newFakeBot(t)
+	admin, member := &discordgo.User{ID: "2", Username: "admin"}, &discordgo.User{ID: "3", Username: "member"}
+	bot.AddChannel("10", "11")
+	bot.AddChannel("10", "12")
+	bot.AddChannel("20", "21")
+	bot.AddMember("10", admin)
+	bot.AddMember("10", member)
+	bot.SetPermissions(admin.ID, "11", FakeDefaultPermissions|discordgo.PermissionManageGuild)
+	// Only members who can manage the guild can configure the bot
+	message := bot.InjectMessage("10", "11", member, "!config prefix ?")
+	if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != EmojiError {
+		t.Fatal("message should've been reacted to with", EmojiError, "got", reactions)
+	}
+	scenarios := []struct {
+		Name            string
+		Command         string
+		ExpectedSuccess bool
+	}{
+		{Name: "prefix", Command: "!config prefix ?", ExpectedSuccess: true},
+		{Name: "prefix-with-spaces", Command: "?config prefix a b", ExpectedSuccess: false},
+		{Name: "reaction-duration", Command: "?config reaction-duration 30 minutes", ExpectedSuccess: true},
+		{Name: "reaction-duration-too-long", Command: "?config reaction-duration 10 years", ExpectedSuccess: false},
+		{Name: "max-reminders", Command: "?config max-reminders 2", ExpectedSuccess: true},
+		{Name: "max-reminders-too-high", Command: "?config max-reminders 1000", ExpectedSuccess: false},
+		{Name: "channels", Command: "?config channels <#11>", ExpectedSuccess: true},
+		{Name: "channels-in-another-guild", Command: "?config channels <#11> <#21>", ExpectedSuccess: false},
+		{Name: "channel-delivery", Command: "?config channel-delivery off", ExpectedSuccess: true},
+		{Name: "unknown-key", Command: "?config color blue", ExpectedSuccess: false},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			message := bot.InjectMessage("10", "11", admin, scenario.Command)
+			expectedReaction := EmojiError
+			if scenario.ExpectedSuccess {
+				expectedReaction = EmojiSuccess
+			}
+			if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != expectedReaction {
+				t.Error("message should've been reacted to with", expectedReaction, "got", reactions)
+			}
+		})
+	}
+	// The default prefix is no longer used in the guild
+	message = bot.InjectMessage("10", "11", member, "!remindme 2h")
+	if reactions := bot.Reactions(message.ID); len(reactions) != 0 {
+		t.Error("command with the default prefix should've been ignored, got", reactions)
+	}
+	// Commands are ignored outside of the allowed channels
+	message = bot.InjectMessage("10", "12", member, "?remindme 2h")
+	if reactions := bot.Reactions(message.ID); len(reactions) != 0 {
+		t.Error("command outside of the allowed channels should've been ignored, got", reactions)
+	}
+	// Reminders created from the guild can't be delivered in a channel
+	message = bot.InjectMessage("10", "11", member, "?remindhere 2h")
+	if replies := bot.SentMessages("11"); !strings.Contains(replies[len(replies)-1].Content, "only be delivered by direct message") {
+		t.Error("member should've been told that reminders can only be delivered by direct message, got", replies[len(replies)-1].Content)
+	}
+	// Reactions create reminders due in the configured duration, up to the configured maximum number of reminders
+	bot.InjectReactionAdd("10", "11", message.ID, member.ID, reminderEmojis[0])
+	reminders, _ := store.GetRemindersByCreatorID(context.Background(), member.ID, 0, 10)
+	if len(reminders) != 1 || time.Until(reminders[0].Time) > 30*time.Minute || time.Until(reminders[0].Time) < 29*time.Minute {
+		t.Fatal("reaction should've created a reminder due in 30 minutes, got", reminders)
+	}
+	message = bot.InjectMessage("10", "11", member, "?remindme 2h")
+	if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != EmojiSuccess {
+		t.Error("message should've been reacted to with", EmojiSuccess, "got", reactions)
+	}
+	bot.InjectMessage("10", "11", member, "?remindme 3h")
+	if replies := bot.SentMessages("11"); !strings.Contains(replies[len(replies)-1].Content, "maximum number of reminders a single user can have (2)") {
+		t.Error("member should've been told that they reached the maximum number of reminders, got", replies[len(replies)-1].Content)
+	}
+}
+
+func TestHandleMessage_ConfigMaximumRemindersWithConfiguredLimit(t *testing.T) {
+	cfg := config.Default()
+	cfg.Limits.MaximumNumberOfRemindersPerUser = 1
+	cfg.Limits.MaximumConfigurableRemindersPerUser = 2
+	bot, store := newFakeBotWithConfig(t, cfg)
+	admin := &discordgo.User{ID: "2", Username: "admin"}
+	bot.AddChannel("10", "11")
+	bot.AddMember("10", admin)
+	bot.SetPermissions(admin.ID, "11", FakeDefaultPermissions|discordgo.PermissionManageGuild)
+	scenarios := []struct {
+		Name            string
+		Command         string
+		ExpectedSuccess bool
+	}{
+		{Name: "max-reminders-within-configured-limit", Command: "!config max-reminders 2", ExpectedSuccess: true},
+		{Name: "max-reminders-above-configured-limit", Command: "!config max-reminders 3", ExpectedSuccess: false},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			message := bot.InjectMessage("10", "11", admin, scenario.Command)
+			expectedReaction := EmojiError
+			if scenario.ExpectedSuccess {
+				expectedReaction = EmojiSuccess
+			}
+			if reactions := bot.Reactions(message.ID); len(reactions) != 1 || reactions[0] != expectedReaction {
+				t.Error("message should've been reacted to with", expectedReaction, "got", reactions)
+			}
+		})
+	}
+	// A maximum configured before the configured limit was lowered is bounded by the configured limit
+	_ = store.UpsertGuildSettings(context.Background(), &core.GuildSettings{GuildID: "10", MaximumRemindersPerUser: 50})
+	for i := 0; i < 3; i++ {
+		bot.InjectMessage("10", "11", admin, "!remindme 2h")
+	}
+	if count, _ := store.CountRemindersByCreatorID(context.Background(), admin.ID); count != 2 {
+		t.Error("admin should've been limited to 2 reminders, got", count)
+	}
+}
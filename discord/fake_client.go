@@ -0,0 +1,449 @@
+package discord
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/TwiN/discord-reminder-bot/database"
+	"github.com/bwmarrin/discordgo"
+)
+
+// FakeClient is a Client that simulates Discord locally, which makes it possible to exercise the bot without any network
+// access. It records the messages, reactions and interaction responses sent by the bot, lets guilds be populated with
+// channels, members and roles, can inject events into the bot's handlers, and can make specific operations fail.
+type FakeClient struct {
+	user *discordgo.User
+
+	// store is the store passed to the handlers that events are injected into
+	store database.Store
+
+	channels               map[string]*discordgo.Channel
+	directMessageChannels  map[string]*discordgo.Channel
+	members                map[string]*discordgo.Member
+	roles                  map[string]*discordgo.Role
+	permissions            map[string]int64
+	messages               map[string]*discordgo.Message
+	sentMessages           []*discordgo.Message
+	interactionResponses   []*discordgo.InteractionResponse
+	applicationCommands    map[string][]*discordgo.ApplicationCommand
+	listeningStatus        string
+	failures               map[string]error
+	lastGeneratedSnowflake int64
+
+	mutex sync.Mutex
+}
+
+// FakeDefaultPermissions are the permissions of users in channels for which no permissions were set with
+// FakeClient.SetPermissions, which are those of a regular member
+const FakeDefaultPermissions = int64(discordgo.PermissionViewChannel | discordgo.PermissionReadMessageHistory | discordgo.PermissionSendMessages | discordgo.PermissionSendMessagesInThreads)
+
+// NewFakeClient creates a FakeClient whose bot user has the given ID, and whose injected events are handled with store
+func NewFakeClient(botUserID string, store database.Store) *FakeClient {
+	return &FakeClient{
+		user:                   &discordgo.User{ID: botUserID, Username: "RemindMe", Bot: true},
+		store:                  store,
+		channels:               make(map[string]*discordgo.Channel),
+		directMessageChannels:  make(map[string]*discordgo.Channel),
+		members:                make(map[string]*discordgo.Member),
+		roles:                  make(map[string]*discordgo.Role),
+		permissions:            make(map[string]int64),
+		messages:               make(map[string]*discordgo.Message),
+		applicationCommands:    make(map[string][]*discordgo.ApplicationCommand),
+		failures:               make(map[string]error),
+		lastGeneratedSnowflake: 1000,
+	}
+}
+
+// NewFakeRESTError creates an error similar to the one returned by Discord's API for a given status code
+func NewFakeRESTError(statusCode int) error {
+	return &discordgo.RESTError{
+		Response:     &http.Response{StatusCode: statusCode, Status: http.StatusText(statusCode)},
+		ResponseBody: []byte(http.StatusText(statusCode)),
+	}
+}
+
+// AddChannel adds a channel to a guild
+func (c *FakeClient) AddChannel(guildID, channelID string) *discordgo.Channel {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	channel := &discordgo.Channel{ID: channelID, GuildID: guildID, Type: discordgo.ChannelTypeGuildText}
+	c.channels[channelID] = channel
+	return channel
+}
+
+// AddMember adds a user to a guild
+func (c *FakeClient) AddMember(guildID string, user *discordgo.User, roleIDs ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.members[guildID+"/"+user.ID] = &discordgo.Member{GuildID: guildID, User: user, Roles: roleIDs}
+}
+
+// AddRole adds a role to a guild
+func (c *FakeClient) AddRole(guildID string, role *discordgo.Role) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.roles[guildID+"/"+role.ID] = role
+}
+
+// SetPermissions sets the permissions of a user in a channel, replacing FakeDefaultPermissions
+func (c *FakeClient) SetPermissions(userID, channelID string, permissions int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.permissions[userID+"/"+channelID] = permissions
+}
+
+// Fail makes every call to an operation of the Client interface (e.g. "ChannelMessageSendComplex") fail with err.
+// If targetID is not empty, only the calls targeting that channel fail, or that user for UserChannelCreate and
+// GuildMember, or that role for GuildRole. Passing a nil err makes the operation succeed again.
+func (c *FakeClient) Fail(operation, targetID string, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err == nil {
+		delete(c.failures, operation+"/"+targetID)
+	} else {
+		c.failures[operation+"/"+targetID] = err
+	}
+}
+
+// DirectMessageChannelID returns the ID of the direct message channel with a user, opening it if necessary
+func (c *FakeClient) DirectMessageChannelID(userID string) string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.openDirectMessageChannel(userID).ID
+}
+
+// Message retrieves a message sent by the bot or injected with InjectMessage, reflecting any edit made since
+func (c *FakeClient) Message(messageID string) *discordgo.Message {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.messages[messageID]
+}
+
+// SentMessages returns the messages sent by the bot in a channel, in the order they were sent
+func (c *FakeClient) SentMessages(channelID string) []*discordgo.Message {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var messages []*discordgo.Message
+	for _, message := range c.sentMessages {
+		if message.ChannelID == channelID {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// Reactions returns the emojis that the bot reacted to a message with
+func (c *FakeClient) Reactions(messageID string) []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var emojis []string
+	if message, exists := c.messages[messageID]; exists {
+		for _, reaction := range message.Reactions {
+			emojis = append(emojis, reaction.Emoji.Name)
+		}
+	}
+	return emojis
+}
+
+// InteractionResponses returns the responses to interactions sent by the bot, in the order they were sent
+func (c *FakeClient) InteractionResponses() []*discordgo.InteractionResponse {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]*discordgo.InteractionResponse(nil), c.interactionResponses...)
+}
+
+// ApplicationCommands returns the application commands registered by the bot in a guild, or its global application
+// commands if guildID is empty
+func (c *FakeClient) ApplicationCommands(guildID string) []*discordgo.ApplicationCommand {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]*discordgo.ApplicationCommand(nil), c.applicationCommands[guildID]...)
+}
+
+// ListeningStatus returns the last "Listening to" status set by the bot
+func (c *FakeClient) ListeningStatus() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.listeningStatus
+}
+
+// InjectMessage simulates a user sending a message in a channel and passes it to HandleMessage.
+// If guildID is empty, the channel is assumed to be a direct message channel.
+func (c *FakeClient) InjectMessage(guildID, channelID string, author *discordgo.User, content string) *discordgo.Message {
+	c.mutex.Lock()
+	message := &discordgo.Message{ID: c.generateSnowflake(), GuildID: guildID, ChannelID: channelID, Author: author, Content: content}
+	c.messages[message.ID] = message
+	c.mutex.Unlock()
+	HandleMessage(c, c.store, &discordgo.MessageCreate{Message: message})
+	return message
+}
+
+// InjectReactionAdd simulates a user reacting to a message and passes the reaction to HandleReactionAdd
+func (c *FakeClient) InjectReactionAdd(guildID, channelID, messageID, userID, emoji string) {
+	HandleReactionAdd(c, c.store, &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		MessageID: messageID,
+		UserID:    userID,
+		Emoji:     discordgo.Emoji{Name: emoji},
+	}})
+}
+
+// InjectReactionRemove simulates a user removing their reaction from a message and passes it to HandleReactionRemove
+func (c *FakeClient) InjectReactionRemove(guildID, channelID, messageID, userID, emoji string) {
+	HandleReactionRemove(c, c.store, &discordgo.MessageReactionRemove{MessageReaction: &discordgo.MessageReaction{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		MessageID: messageID,
+		UserID:    userID,
+		Emoji:     discordgo.Emoji{Name: emoji},
+	}})
+}
+
+// InjectInteraction simulates a user interacting with the bot (e.g. using a slash command or clicking on a button) and
+// passes the interaction to HandleInteraction
+func (c *FakeClient) InjectInteraction(interaction *discordgo.Interaction) {
+	c.mutex.Lock()
+	if len(interaction.ID) == 0 {
+		interaction.ID = c.generateSnowflake()
+	}
+	c.mutex.Unlock()
+	HandleInteraction(c, c.store, &discordgo.InteractionCreate{Interaction: interaction})
+}
+
+func (c *FakeClient) BotUser() *discordgo.User {
+	return c.user
+}
+
+func (c *FakeClient) Channel(channelID string) (*discordgo.Channel, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("Channel", channelID); err != nil {
+		return nil, err
+	}
+	if channel, exists := c.channels[channelID]; exists {
+		return channel, nil
+	}
+	return nil, NewFakeRESTError(http.StatusNotFound)
+}
+
+func (c *FakeClient) ChannelMessage(channelID, messageID string) (*discordgo.Message, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("ChannelMessage", channelID); err != nil {
+		return nil, err
+	}
+	if message, exists := c.messages[messageID]; exists && message.ChannelID == channelID {
+		return message, nil
+	}
+	return nil, NewFakeRESTError(http.StatusNotFound)
+}
+
+func (c *FakeClient) GuildMember(guildID, userID string) (*discordgo.Member, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("GuildMember", userID); err != nil {
+		return nil, err
+	}
+	if member, exists := c.members[guildID+"/"+userID]; exists {
+		return member, nil
+	}
+	return nil, NewFakeRESTError(http.StatusNotFound)
+}
+
+func (c *FakeClient) GuildRole(guildID, roleID string) (*discordgo.Role, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("GuildRole", roleID); err != nil {
+		return nil, err
+	}
+	if role, exists := c.roles[guildID+"/"+roleID]; exists {
+		return role, nil
+	}
+	return nil, NewFakeRESTError(http.StatusNotFound)
+}
+
+func (c *FakeClient) UserChannelPermissions(userID, channelID string) (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("UserChannelPermissions", channelID); err != nil {
+		return 0, err
+	}
+	if permissions, exists := c.permissions[userID+"/"+channelID]; exists {
+		return permissions, nil
+	}
+	return FakeDefaultPermissions, nil
+}
+
+func (c *FakeClient) UserChannelCreate(userID string) (*discordgo.Channel, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("UserChannelCreate", userID); err != nil {
+		return nil, err
+	}
+	return c.openDirectMessageChannel(userID), nil
+}
+
+func (c *FakeClient) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("ChannelMessageSendComplex", channelID); err != nil {
+		return nil, err
+	}
+	return c.sendMessage(&discordgo.Message{
+		ChannelID:        channelID,
+		Content:          data.Content,
+		Embeds:           data.Embeds,
+		Components:       data.Components,
+		MessageReference: data.Reference,
+	})
+}
+
+func (c *FakeClient) ChannelMessageSendReply(channelID, content string, reference *discordgo.MessageReference) (*discordgo.Message, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("ChannelMessageSendReply", channelID); err != nil {
+		return nil, err
+	}
+	return c.sendMessage(&discordgo.Message{ChannelID: channelID, Content: content, MessageReference: reference})
+}
+
+func (c *FakeClient) ChannelMessageEditEmbed(channelID, messageID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	return c.ChannelMessageEditComplex(&discordgo.MessageEdit{Channel: channelID, ID: messageID, Embeds: &[]*discordgo.MessageEmbed{embed}})
+}
+
+func (c *FakeClient) ChannelMessageEditComplex(edit *discordgo.MessageEdit) (*discordgo.Message, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("ChannelMessageEditComplex", edit.Channel); err != nil {
+		return nil, err
+	}
+	message, exists := c.messages[edit.ID]
+	if !exists || message.ChannelID != edit.Channel {
+		return nil, NewFakeRESTError(http.StatusNotFound)
+	}
+	if edit.Content != nil {
+		message.Content = *edit.Content
+	}
+	if edit.Embeds != nil {
+		message.Embeds = *edit.Embeds
+	}
+	if edit.Components != nil {
+		message.Components = *edit.Components
+	}
+	return message, nil
+}
+
+func (c *FakeClient) MessageReactionAdd(channelID, messageID, emoji string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("MessageReactionAdd", channelID); err != nil {
+		return err
+	}
+	message, exists := c.messages[messageID]
+	if !exists || message.ChannelID != channelID {
+		return NewFakeRESTError(http.StatusNotFound)
+	}
+	for _, reaction := range message.Reactions {
+		if reaction.Emoji.Name == emoji {
+			return nil
+		}
+	}
+	message.Reactions = append(message.Reactions, &discordgo.MessageReactions{Count: 1, Me: true, Emoji: &discordgo.Emoji{Name: emoji}})
+	return nil
+}
+
+// MessageReactionRemove removes a reaction from a message
+// Since the reactions of users other than the bot are not tracked, only "@me" and the bot's ID are supported as userID.
+func (c *FakeClient) MessageReactionRemove(channelID, messageID, emoji, userID string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("MessageReactionRemove", channelID); err != nil {
+		return err
+	}
+	message, exists := c.messages[messageID]
+	if !exists || message.ChannelID != channelID {
+		return NewFakeRESTError(http.StatusNotFound)
+	}
+	if userID != "@me" && userID != c.user.ID {
+		return nil
+	}
+	for i, reaction := range message.Reactions {
+		if reaction.Emoji.Name == emoji {
+			message.Reactions = append(message.Reactions[:i], message.Reactions[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (c *FakeClient) InteractionRespond(interaction *discordgo.Interaction, response *discordgo.InteractionResponse) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("InteractionRespond", interaction.ChannelID); err != nil {
+		return err
+	}
+	c.interactionResponses = append(c.interactionResponses, response)
+	return nil
+}
+
+func (c *FakeClient) ApplicationCommandBulkOverwrite(guildID string, commands []*discordgo.ApplicationCommand) ([]*discordgo.ApplicationCommand, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.failure("ApplicationCommandBulkOverwrite", guildID); err != nil {
+		return nil, err
+	}
+	c.applicationCommands[guildID] = commands
+	return commands, nil
+}
+
+func (c *FakeClient) UpdateListeningStatus(status string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.listeningStatus = status
+	return nil
+}
+
+// sendMessage assigns an ID to a message sent by the bot and keeps track of it
+// Must be called with the mutex locked.
+func (c *FakeClient) sendMessage(message *discordgo.Message) (*discordgo.Message, error) {
+	channel, exists := c.channels[message.ChannelID]
+	if !exists {
+		return nil, NewFakeRESTError(http.StatusNotFound)
+	}
+	message.ID = c.generateSnowflake()
+	message.GuildID = channel.GuildID
+	message.Author = c.user
+	c.messages[message.ID] = message
+	c.sentMessages = append(c.sentMessages, message)
+	return message, nil
+}
+
+// openDirectMessageChannel returns the direct message channel with a user, creating it if it doesn't exist yet
+// Must be called with the mutex locked.
+func (c *FakeClient) openDirectMessageChannel(userID string) *discordgo.Channel {
+	channel, exists := c.directMessageChannels[userID]
+	if !exists {
+		channel = &discordgo.Channel{ID: c.generateSnowflake(), Type: discordgo.ChannelTypeDM, Recipients: []*discordgo.User{{ID: userID}}}
+		c.directMessageChannels[userID] = channel
+		c.channels[channel.ID] = channel
+	}
+	return channel
+}
+
+// failure returns the error that an operation targeting targetID should fail with, if any
+// Must be called with the mutex locked.
+func (c *FakeClient) failure(operation, targetID string) error {
+	if err, exists := c.failures[operation+"/"+targetID]; exists {
+		return err
+	}
+	return c.failures[operation+"/"]
+}
+
+// generateSnowflake generates a unique ID
+// Must be called with the mutex locked.
+func (c *FakeClient) generateSnowflake() string {
+	c.lastGeneratedSnowflake++
+	return strconv.FormatInt(c.lastGeneratedSnowflake, 10)
+}
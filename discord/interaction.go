@@ -0,0 +1,351 @@
+package discord
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/database"
+	"github.com/TwiN/discord-reminder-bot/i18n"
+	"github.com/TwiN/discord-reminder-bot/logging"
+	"github.com/TwiN/discord-reminder-bot/metrics"
+	"github.com/bwmarrin/discordgo"
+)
+
+var messageLinkRegex = regexp.MustCompile(`^https://(?:(?:ptb|canary)\.)?discord(?:app)?\.com/channels/(@me|\d+)/(\d+)/(\d+)$`)
+
+// generateApplicationCommands returns the bot's slash commands, which depend on the limits configured
+func generateApplicationCommands() []*discordgo.ApplicationCommand {
+	return []*discordgo.ApplicationCommand{
+		{
+			Name:        "remindme",
+			Description: "Create a reminder",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "time",
+					Description: "When to remind you, e.g. 2h30m, at 15:30, tomorrow at 8am or friday 9am",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "note",
+					Description: "Note to attach to the reminder",
+					MaxLength:   limits.MaximumNoteLength,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "message",
+					Description: "Link to the message to be reminded about",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "every",
+					Description: "Interval at which the reminder repeats, e.g. 24h, 7d until 2030-12-31 or 1d 10 times",
+				},
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Channel in which to deliver the reminder instead of sending it to you by direct message",
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews, discordgo.ChannelTypeGuildPublicThread, discordgo.ChannelTypeGuildPrivateThread, discordgo.ChannelTypeGuildNewsThread},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "User to remind instead of you",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionRole,
+					Name:        "role",
+					Description: "Role to remind instead of you, in the current channel unless another channel is specified",
+				},
+			},
+		},
+		{
+			Name:        "list",
+			Description: "List your reminders",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "page",
+					Description: "Page of reminders to display",
+				},
+			},
+		},
+		{
+			Name:        "delete",
+			Description: "Delete one of your reminders",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "id",
+					Description: "ID of the reminder, as shown by /list",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "edit",
+			Description: "Edit one of your reminders",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "id",
+					Description: "ID of the reminder, as shown by /list",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "time",
+					Description: "New time for the reminder, e.g. 2h30m, at 15:30, tomorrow at 8am or friday 9am",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "note",
+					Description: "New note for the reminder",
+					MaxLength:   limits.MaximumNoteLength,
+				},
+			},
+		},
+	}
+}
+
+// registerApplicationCommands registers the bot's slash commands globally
+func registerApplicationCommands(bot Client) {
+	if _, err := bot.ApplicationCommandBulkOverwrite("", generateApplicationCommands()); err != nil {
+		slog.Error("[discord][registerApplicationCommands] Failed to register application commands", "error", err)
+	}
+}
+
+func HandleInteraction(bot Client, store database.Store, interaction *discordgo.InteractionCreate) {
+	ctx := logging.WithCorrelationID(context.Background())
+	switch interaction.Type {
+	case discordgo.InteractionMessageComponent:
+		handleComponentInteraction(ctx, bot, store, interaction)
+		return
+	case discordgo.InteractionModalSubmit:
+		handleModalSubmitInteraction(ctx, bot, store, interaction)
+		return
+	case discordgo.InteractionApplicationCommand:
+	default:
+		return
+	}
+	data := interaction.ApplicationCommandData()
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(data.Options))
+	for _, option := range data.Options {
+		options[option.Name] = option
+	}
+	slog.InfoContext(ctx, "[discord][HandleInteraction] Received command", "command", data.Name, "interaction_id", interaction.ID, "user_id", getInteractionUserID(interaction), "guild_id", interaction.GuildID)
+	switch data.Name {
+	case "remindme":
+		handleRemindMeCommand(ctx, bot, store, interaction, options)
+	case "list":
+		handleListCommand(ctx, bot, store, interaction, options)
+	case "delete":
+		handleDeleteCommand(ctx, bot, store, interaction, options)
+	case "edit":
+		handleEditCommand(ctx, bot, store, interaction, options)
+	}
+}
+
+func handleRemindMeCommand(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	userID := getInteractionUserID(interaction)
+	language := getLanguage(ctx, store, userID, interaction.GuildID)
+	if len(interaction.GuildID) > 0 && !isChannelAllowed(bot, getGuildSettings(ctx, store, interaction.GuildID), interaction.ChannelID) {
+		respondToInteraction(ctx, bot, interaction, language.Translate("I cannot be used in this channel"))
+		return
+	}
+	when, rest, err := parseReminderTime(ctx, store, userID, options["time"].StringValue())
+	if err != nil {
+		respondToInteraction(ctx, bot, interaction, language.Error(err))
+		return
+	}
+	if len(strings.TrimSpace(rest)) > 0 {
+		respondToInteraction(ctx, bot, interaction, language.Sprintf("Unexpected text after the time: `%s`", strings.TrimSpace(rest)))
+		return
+	}
+	reminder := &core.Reminder{
+		CreatorID: userID,
+		UserID:    userID,
+		// When no message is specified, the reminder links to the channel in which the command was used
+		MessageLink: generateChannelLink(interaction.GuildID, interaction.ChannelID),
+		Time:        when,
+	}
+	if option, exists := options["message"]; exists {
+		messageLink := strings.TrimSpace(option.StringValue())
+		if err = validateMessageLink(bot, userID, messageLink); err != nil {
+			respondToInteraction(ctx, bot, interaction, language.Error(err))
+			return
+		}
+		reminder.MessageLink = messageLink
+	}
+	if option, exists := options["every"]; exists {
+		rest, err = parseRepeatClause("every "+option.StringValue(), reminder)
+		if err == nil && len(strings.TrimSpace(rest)) > 0 {
+			err = i18n.Errorf("unexpected text after the interval: %s", strings.TrimSpace(rest))
+		}
+		if err != nil {
+			respondToInteraction(ctx, bot, interaction, language.Sprintf(InvalidRepeatClauseMessageFormat, err))
+			return
+		}
+	}
+	if option, exists := options["user"]; exists {
+		reminder.UserID = option.UserValue(nil).ID
+	}
+	if option, exists := options["role"]; exists {
+		reminder.UserID, reminder.RoleID, reminder.ChannelID = "", option.RoleValue(nil, interaction.GuildID).ID, interaction.ChannelID
+	}
+	if err = validateRecipient(ctx, bot, store, interaction.GuildID, interaction.ChannelID, reminder); err != nil {
+		respondToInteraction(ctx, bot, interaction, language.Error(err))
+		return
+	}
+	if option, exists := options["channel"]; exists {
+		reminder.ChannelID = option.ChannelValue(nil).ID
+	}
+	if reminder.IsDeliveredInChannel() {
+		if err = validateDeliveryChannel(ctx, bot, store, interaction.GuildID, userID, reminder.ChannelID); err != nil {
+			respondToInteraction(ctx, bot, interaction, language.Error(err))
+			return
+		}
+	}
+	if option, exists := options["note"]; exists {
+		reminder.Note = strings.TrimSpace(option.StringValue())
+	}
+	if err = createReminder(ctx, bot, store, interaction.GuildID, metrics.SourceSlashCommand, reminder); err != nil {
+		slog.ErrorContext(ctx, "[discord][handleRemindMeCommand] Failed to create reminder", "error", err)
+		respondToInteraction(ctx, bot, interaction, language.Error(err))
+		return
+	}
+	respondToInteraction(ctx, bot, interaction, reminder.GenerateNotificationMessageContent(language))
+}
+
+func handleListCommand(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	userID := getInteractionUserID(interaction)
+	language := getLanguage(ctx, store, userID, interaction.GuildID)
+	page := 1
+	if option, exists := options["page"]; exists && option.IntValue() > 0 {
+		page = int(option.IntValue())
+	}
+	directMessageChannel, err := bot.UserChannelCreate(userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][handleListCommand] Failed to open direct message", "error", err)
+		respondToInteraction(ctx, bot, interaction, language.Translate("Error: failed to open direct message"))
+		return
+	}
+	embed, numberOfPages, err := createReminderListMessageEmbed(ctx, store, language, directMessageChannel.ID, userID, page)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][handleListCommand] Failed to create embed message", "error", err)
+		respondToInteraction(ctx, bot, interaction, language.Translate("Error: failed to retrieve your reminders"))
+		return
+	}
+	err = bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: generateReminderListComponents(language, page, numberOfPages),
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "[discord][handleListCommand] Failed to respond to interaction", "error", err)
+	}
+}
+
+func handleDeleteCommand(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	reminder, directMessageChannelID, ok := getReminderForInteraction(ctx, bot, store, interaction, options["id"].IntValue())
+	if !ok {
+		return
+	}
+	deleteReminder(ctx, bot, store, directMessageChannelID, reminder)
+	metrics.RemindersDeleted.Inc()
+	language := getLanguage(ctx, store, reminder.CreatorID, interaction.GuildID)
+	respondToInteraction(ctx, bot, interaction, language.Sprintf("Reminder #%d has been deleted.", reminder.ID))
+}
+
+func handleEditCommand(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	if len(interaction.GuildID) > 0 && !isChannelAllowed(bot, getGuildSettings(ctx, store, interaction.GuildID), interaction.ChannelID) {
+		respondToInteraction(ctx, bot, interaction, getLanguage(ctx, store, getInteractionUserID(interaction), interaction.GuildID).Translate("I cannot be used in this channel"))
+		return
+	}
+	reminder, directMessageChannelID, ok := getReminderForInteraction(ctx, bot, store, interaction, options["id"].IntValue())
+	if !ok {
+		return
+	}
+	language := getLanguage(ctx, store, reminder.CreatorID, interaction.GuildID)
+	if option, exists := options["time"]; exists {
+		when, rest, err := parseReminderTime(ctx, store, reminder.CreatorID, option.StringValue())
+		if err != nil {
+			respondToInteraction(ctx, bot, interaction, language.Error(err))
+			return
+		}
+		if len(strings.TrimSpace(rest)) > 0 {
+			respondToInteraction(ctx, bot, interaction, language.Sprintf("Unexpected text after the time: `%s`", strings.TrimSpace(rest)))
+			return
+		}
+		reminder.Time = when
+	}
+	if option, exists := options["note"]; exists {
+		reminder.Note = strings.TrimSpace(option.StringValue())
+	}
+	if err := updateReminder(ctx, store, reminder); err != nil {
+		slog.ErrorContext(ctx, "[discord][handleEditCommand] Failed to update reminder", "error", err)
+		respondToInteraction(ctx, bot, interaction, language.Translate("Error: failed to update reminder"))
+		return
+	}
+	_, _ = updateExistingMessage(bot, directMessageChannelID, reminder.NotificationMessageID, "", reminder.GenerateNotificationMessageContent(language))
+	respondToInteraction(ctx, bot, interaction, reminder.GenerateNotificationMessageContent(language))
+}
+
+// getReminderForInteraction retrieves a reminder by its ID as well as the ID of the direct message channel in which its
+// notification message was sent. If the reminder doesn't exist or doesn't belong to the user who triggered the
+// interaction, the user is notified and false is returned.
+func getReminderForInteraction(ctx context.Context, bot Client, store database.Store, interaction *discordgo.InteractionCreate, id int64) (*core.Reminder, string, bool) {
+	userID := getInteractionUserID(interaction)
+	language := getLanguage(ctx, store, userID, interaction.GuildID)
+	reminder, err := store.GetReminderByID(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][getReminderForInteraction] Failed to retrieve reminder by id", "error", err)
+		respondToInteraction(ctx, bot, interaction, language.Translate("Error: failed to retrieve reminder"))
+		return nil, "", false
+	}
+	if reminder == nil || reminder.CreatorID != userID {
+		respondToInteraction(ctx, bot, interaction, language.Sprintf("You have no reminder with ID %d.", id))
+		return nil, "", false
+	}
+	directMessageChannel, err := bot.UserChannelCreate(userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][getReminderForInteraction] Failed to open direct message", "error", err)
+		respondToInteraction(ctx, bot, interaction, language.Translate("Error: failed to open direct message"))
+		return nil, "", false
+	}
+	localizeReminder(ctx, store, reminder)
+	return reminder, directMessageChannel.ID, true
+}
+
+// respondToInteraction responds to an interaction with a message that is only visible to the user who triggered it
+func respondToInteraction(ctx context.Context, bot Client, interaction *discordgo.InteractionCreate, content string) {
+	err := bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "[discord][respondToInteraction] Failed to respond to interaction", "error", err)
+	}
+}
+
+// getInteractionUserID returns the ID of the user who triggered an interaction
+// Interactions from guilds have the user in Member, while interactions from direct messages have it in User.
+func getInteractionUserID(interaction *discordgo.InteractionCreate) string {
+	if interaction.Member != nil && interaction.Member.User != nil {
+		return interaction.Member.User.ID
+	}
+	if interaction.User != nil {
+		return interaction.User.ID
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestRegisterApplicationCommands(t *testing.T) {
+	bot, _ := newFakeBot(t)
+	registerApplicationCommands(bot)
+	commands := bot.ApplicationCommands("")
+	if len(commands) != len(generateApplicationCommands()) {
+		t.Fatalf("expected %d global application commands, got %d", len(generateApplicationCommands()), len(commands))
+	}
+	for i, expectedName := range []string{"remindme", "list", "delete", "edit"} {
+		if commands[i].Name != expectedName {
+			t.Errorf("expected command at index %d to be %s, got %s", i, expectedName, commands[i].Name)
+		}
+	}
+}
+
+func TestHandleInteraction_EditOnlyInAllowedChannels(t *testing.T) {
+	bot, store := newFakeBot(t)
+	admin := &discordgo.User{ID: "2", Username: "admin"}
+	bot.AddChannel("10", "11")
+	bot.AddChannel("10", "12")
+	bot.AddMember("10", admin)
+	bot.SetPermissions(admin.ID, "11", FakeDefaultPermissions|discordgo.PermissionManageGuild)
+	bot.InjectMessage("10", "11", admin, "!config channels <#11>")
+	bot.InjectMessage("10", "11", admin, "!remindme 2h water the plants")
+	reminders, _ := store.GetRemindersByCreatorID(context.Background(), admin.ID, 0, 1)
+	if len(reminders) != 1 {
+		t.Fatal("reminder should've been created")
+	}
+	scenarios := []struct {
+		Name         string
+		ChannelID    string
+		ExpectedNote string
+	}{
+		{
+			Name:         "disallowed-channel",
+			ChannelID:    "12",
+			ExpectedNote: "water the plants",
+		},
+		{
+			Name:         "allowed-channel",
+			ChannelID:    "11",
+			ExpectedNote: "water the flowers",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			bot.InjectInteraction(&discordgo.Interaction{
+				Type:      discordgo.InteractionApplicationCommand,
+				GuildID:   "10",
+				ChannelID: scenario.ChannelID,
+				Member:    &discordgo.Member{User: admin},
+				Data: discordgo.ApplicationCommandInteractionData{
+					Name: "edit",
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "id", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(reminders[0].ID)},
+						{Name: "note", Type: discordgo.ApplicationCommandOptionString, Value: "water the flowers"},
+					},
+				},
+			})
+			reminder, _ := store.GetReminderByNotificationMessageID(context.Background(), reminders[0].NotificationMessageID)
+			if reminder.Note != scenario.ExpectedNote {
+				t.Errorf("expected note to be %q, got %q", scenario.ExpectedNote, reminder.Note)
+			}
+		})
+	}
+	if responses := bot.InteractionResponses(); len(responses) == 0 || responses[0].Data.Content != "I cannot be used in this channel" {
+		t.Error("user should've been told that the bot cannot be used in this channel, got", responses)
+	}
+}
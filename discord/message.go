@@ -1,114 +1,695 @@
 package discord
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/database"
 	"github.com/TwiN/discord-reminder-bot/format"
+	"github.com/TwiN/discord-reminder-bot/i18n"
+	"github.com/TwiN/discord-reminder-bot/logging"
+	"github.com/TwiN/discord-reminder-bot/metrics"
 	"github.com/bwmarrin/discordgo"
 )
 
-func HandleMessage(bot *discordgo.Session, message *discordgo.MessageCreate) {
-	if message.Author.Bot || message.Author.ID == bot.State.User.ID {
+var (
+	channelOptionRegex       = regexp.MustCompile(`(?i)(?:^|\s)--channel\s+<?#?(\d+)>?`)
+	channelArgumentRegex     = regexp.MustCompile(`<?#?(\d+)>?`)
+	messageLinkArgumentRegex = regexp.MustCompile(`(?:^|\s)<?(https://(?:(?:ptb|canary)\.)?discord(?:app)?\.com/channels/(?:@me|\d+)/\d+/\d+)>?(?:\s|$)`)
+)
+
+func HandleMessage(bot Client, store database.Store, message *discordgo.MessageCreate) {
+	ctx := logging.WithCorrelationID(context.Background())
+	if message.Author.Bot || message.Author.ID == bot.BotUser().ID {
 		return
 	}
+	guildSettings := getGuildSettings(ctx, store, message.GuildID)
+	commandPrefix := guildSettings.GetCommandPrefix(botCommandPrefix)
 	if strings.HasPrefix(message.Content, botMention) {
 		// If the user mentions the bot, we assume they meant to type the command prefix followed by RemindMe
-		message.Content = strings.Replace(message.Content, botMention, botCommandPrefix+"remindme", 1)
+		message.Content = strings.Replace(message.Content, botMention, commandPrefix+"remindme", 1)
 	}
-	if strings.HasPrefix(message.Content, botCommandPrefix) {
-		command := strings.Replace(strings.Split(message.Content, " ")[0], botCommandPrefix, "", 1)
-		query := strings.TrimSpace(strings.Replace(message.Content, botCommandPrefix+command, "", 1))
+	if strings.HasPrefix(message.Content, commandPrefix) {
+		command := strings.Replace(strings.Split(message.Content, " ")[0], commandPrefix, "", 1)
+		query := strings.TrimSpace(strings.Replace(message.Content, commandPrefix+command, "", 1))
 		command = strings.ToLower(command)
-		log.Printf("[discord][HandleMessage] command=%s; arguments=%s", command, query)
+		// The configuration remains available everywhere, so that a guild can't lock itself out of the bot
+		if len(message.GuildID) > 0 && command != "config" && !isChannelAllowed(bot, guildSettings, message.ChannelID) {
+			return
+		}
+		slog.InfoContext(ctx, "[discord][HandleMessage] Received command", "command", command, "arguments", logging.Sensitive(query), "message_id", message.ID, "user_id", message.Author.ID, "guild_id", message.GuildID)
 		switch command {
 		case "remindme", "remind", "reminder", "help":
-			HandleRemindMe(bot, message, query)
+			HandleRemindMe(ctx, bot, store, message, query)
+		case "remindhere":
+			HandleRemindHere(ctx, bot, store, message, query)
 		case "list", "reminders", "view":
-			HandleListReminders(bot, message)
+			HandleListReminders(ctx, bot, store, message)
+		case "timezone", "tz":
+			HandleTimezone(ctx, bot, store, message, query)
+		case "block":
+			HandleBlock(ctx, bot, store, message, true)
+		case "unblock":
+			HandleBlock(ctx, bot, store, message, false)
+		case "optout":
+			HandleOptOut(ctx, bot, store, message, true)
+		case "optin":
+			HandleOptOut(ctx, bot, store, message, false)
+		case "remindpermission":
+			HandleRemindPermission(ctx, bot, store, message, query)
+		case "language", "lang":
+			HandleLanguage(ctx, bot, store, message, query)
+		case "config":
+			HandleConfig(ctx, bot, store, message, query)
 		}
 	}
 }
 
-func HandleRemindMe(bot *discordgo.Session, message *discordgo.MessageCreate, query string) {
+func HandleRemindMe(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, query string) {
+	if len(query) == 0 {
+		language := getLanguage(ctx, store, message.Author.ID, message.GuildID)
+		commandPrefix := getCommandPrefix(ctx, store, message.GuildID)
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("**Usage:**\n```%sRemindMe [@USER | @ROLE] TIME [every INTERVAL [until YYYY-MM-DD | N times]] [--channel #CHANNEL] [MESSAGE_LINK] NOTE```**Where:**\n- `@USER` or `@ROLE` is an optional user or role to remind instead of you, if you are allowed to remind others\n- `TIME` is either a duration with a format similar to the following: `30m`, `2h`, `6h30m`, `7d12h30m`, `2 hours` or `1 day 3 hours`, or an absolute time like `at 15:30`, `tomorrow at 8am`, `friday 9am` or `2030-11-03 09:00`\n- `INTERVAL` is an optional interval at which the reminder should repeat (e.g. `24h`, `7d`, `1 month`), optionally followed by an end date or a number of occurrences\n- `#CHANNEL` is an optional channel in which the reminder should be delivered instead of being sent to you by direct message. Use `%sRemindHere` to deliver the reminder in the current channel\n- `MESSAGE_LINK` is an optional link to the message to be reminded about. If you use the command in reply to a message, you are reminded about that message instead of your command\n- `NOTE` is an optional note to attach to the reminder with less than %d characters\n\n:information_source: _You can also create a reminder by reacting to a message with any of %s, and you can view your reminders by using `%slist`. Absolute times are interpreted in the timezone set with `%stimezone`._", commandPrefix, commandPrefix, limits.MaximumNoteLength, strings.Join(reminderEmojis, " "), commandPrefix, commandPrefix), message.Reference())
+		return
+	}
+	query, channelID := extractChannelOption(query)
+	handleRemindMeQuery(ctx, bot, store, message, query, channelID)
+}
+
+// HandleRemindHere creates a reminder that is delivered in the channel in which the command was used, unless another
+// channel is specified
+func HandleRemindHere(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, query string) {
 	if len(query) == 0 {
-		_, _ = bot.ChannelMessageSendReply(message.ChannelID, fmt.Sprintf("**Usage:**\n```%sRemindMe DURATION NOTE```**Where:**\n- `DURATION` must have a format similar to the following: `30m`, `2h`, `6h30m`, `30d`, `7d12h30m`\n- `NOTE` is an optional note to attach to the reminder with less than %d characters\n\n:information_source: _You can also create a reminder by reacting with %s, %s or %s to a message, and you can view your reminders by using `%slist`._", botCommandPrefix, MaximumNoteLength, EmojiCreateReminder, EmojiCreateReminderAlt1, EmojiCreateReminderAlt2, botCommandPrefix), message.Reference())
+		HandleRemindMe(ctx, bot, store, message, query)
+		return
+	}
+	query, channelID := extractChannelOption(query)
+	if len(channelID) == 0 {
+		channelID = message.ChannelID
+	}
+	handleRemindMeQuery(ctx, bot, store, message, query, channelID)
+}
+
+// handleRemindMeQuery creates a reminder from the arguments of a RemindMe command, which may start with the mention of
+// the user or role to remind. If channelID is empty, the reminder is delivered by direct message.
+func handleRemindMeQuery(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, query, channelID string) {
+	language := getLanguage(ctx, store, message.Author.ID, message.GuildID)
+	query, userID, roleID := extractRecipient(query)
+	if len(userID) == 0 && len(roleID) == 0 {
+		userID = message.Author.ID
+	}
+	if len(roleID) > 0 && len(channelID) == 0 {
+		// Roles can't be sent direct messages, so reminders for a role are delivered in the current channel by default
+		channelID = message.ChannelID
+	}
+	query, messageLink := extractMessageLink(query)
+	reminder := &core.Reminder{
+		CreatorID:   message.Author.ID,
+		UserID:      userID,
+		RoleID:      roleID,
+		ChannelID:   channelID,
+		MessageLink: messageLink,
+	}
+	// Validate recipient
+	if err := validateRecipient(ctx, bot, store, message.GuildID, message.ChannelID, reminder); err != nil {
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		_, err = bot.ChannelMessageSendReply(message.ChannelID, language.Error(err), message.Reference())
+		if err != nil {
+			slog.WarnContext(ctx, "[discord][handleRemindMeQuery] Failed to reply to message", "error", err)
+		}
 		return
 	}
-	// Validate duration
-	durationArgument := strings.Split(query, " ")[0]
-	duration, err := format.ParseDuration(durationArgument)
+	// If the command is a reply, the user wants to be reminded about the message they replied to
+	if len(reminder.MessageLink) == 0 {
+		reminder.MessageLink = getReplyMessageLink(message.Message)
+	}
+	// Validate the message to be reminded about, if any
+	if len(reminder.MessageLink) > 0 {
+		if err := validateMessageLink(bot, message.Author.ID, reminder.MessageLink); err != nil {
+			_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+			_, err = bot.ChannelMessageSendReply(message.ChannelID, language.Error(err), message.Reference())
+			if err != nil {
+				slog.WarnContext(ctx, "[discord][handleRemindMeQuery] Failed to reply to message", "error", err)
+			}
+			return
+		}
+	} else {
+		reminder.MessageLink = generateMessageLink(message.GuildID, message.ChannelID, message.ID)
+	}
+	// Validate channel, if any
+	if reminder.IsDeliveredInChannel() {
+		if err := validateDeliveryChannel(ctx, bot, store, message.GuildID, message.Author.ID, channelID); err != nil {
+			_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+			_, err = bot.ChannelMessageSendReply(message.ChannelID, language.Error(err), message.Reference())
+			if err != nil {
+				slog.WarnContext(ctx, "[discord][handleRemindMeQuery] Failed to reply to message", "error", err)
+			}
+			return
+		}
+	}
+	// Validate time
+	when, rest, err := parseReminderTime(ctx, store, message.Author.ID, query)
 	if err != nil {
-		log.Printf("[discord][HandleRemindMe] Failed to parse duration '%s' for %s: %s", query, message.Author.String(), err.Error())
+		slog.InfoContext(ctx, "[discord][handleRemindMeQuery] Failed to parse time", "query", logging.Sensitive(query), "user_id", message.Author.ID, "error", err)
 		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
-		_, err = bot.ChannelMessageSendReply(message.ChannelID, fmt.Sprintf("Invalid duration format: ```%s```\n:information_source: _Try something like `45m`, `5h30m`, or `7d`._", err.Error()), message.Reference())
+		_, err = bot.ChannelMessageSendReply(message.ChannelID, language.Error(err), message.Reference())
 		if err != nil {
-			log.Printf("[discord][HandleRemindMe] Failed to reply to message: %s", err.Error())
+			slog.WarnContext(ctx, "[discord][handleRemindMeQuery] Failed to reply to message", "error", err)
 		}
 		return
 	}
-	if duration < MinimumReminderDuration || duration > MaximumReminderDuration {
+	reminder.Time = when
+	// Validate repeat interval, if any
+	rest, err = parseRepeatClause(rest, reminder)
+	if err != nil {
 		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
-		_, err = bot.ChannelMessageSendReply(message.ChannelID, fmt.Sprintf("Duration must between %s and %s", MinimumReminderDuration, MaximumReminderDuration), message.Reference())
+		_, err = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf(InvalidRepeatClauseMessageFormat, err), message.Reference())
 		if err != nil {
-			log.Printf("[discord][HandleRemindMe] Failed to reply to message: %s", err.Error())
+			slog.WarnContext(ctx, "[discord][handleRemindMeQuery] Failed to reply to message", "error", err)
 		}
 		return
 	}
 	// Validate note
-	note := strings.TrimSpace(strings.TrimPrefix(query, durationArgument))
-	if len(note) > MaximumNoteLength {
+	note := strings.TrimSpace(rest)
+	if len(note) > limits.MaximumNoteLength {
 		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
-		_, err = bot.ChannelMessageSendReply(message.ChannelID, fmt.Sprintf("Note must have less than %d characters", MaximumNoteLength), message.Reference())
+		_, err = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("Note must have less than %d characters", limits.MaximumNoteLength), message.Reference())
 		if err != nil {
-			log.Printf("[discord][HandleRemindMe] Failed to reply to message: %s", err.Error())
+			slog.WarnContext(ctx, "[discord][handleRemindMeQuery] Failed to reply to message", "error", err)
 		}
 		return
 	}
+	reminder.Note = note
 	// Create the reminder
-	_, err = createReminder(bot, message.Author.ID, message.GuildID, message.ChannelID, message.ID, note, time.Now().Add(duration))
+	err = createReminder(ctx, bot, store, message.GuildID, metrics.SourceCommand, reminder)
 	if err != nil {
-		log.Printf("[discord][HandleRemindMe] Failed to create reminder: %s", err.Error())
-		_, err = bot.ChannelMessageSendReply(message.ChannelID, "Error: "+err.Error(), message.Reference())
+		slog.ErrorContext(ctx, "[discord][handleRemindMeQuery] Failed to create reminder", "error", err)
+		_, err = bot.ChannelMessageSendReply(message.ChannelID, language.Error(err), message.Reference())
 		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
 		return
 	}
 	_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiSuccess)
 }
 
-func HandleListReminders(bot *discordgo.Session, message *discordgo.MessageCreate) {
+// extractChannelOption removes the "--channel #CHANNEL" option from a query and returns what's left of the query as
+// well as the ID of the channel, or an empty string if the option was not specified
+func extractChannelOption(query string) (string, string) {
+	match := channelOptionRegex.FindStringSubmatchIndex(query)
+	if match == nil {
+		return query, ""
+	}
+	return strings.TrimSpace(strings.TrimSpace(query[:match[0]]) + " " + strings.TrimSpace(query[match[1]:])), query[match[2]:match[3]]
+}
+
+// extractMessageLink removes the first link to a message from a query and returns what's left of the query as well as
+// the link, or an empty string if the query doesn't contain any link to a message
+func extractMessageLink(query string) (string, string) {
+	match := messageLinkArgumentRegex.FindStringSubmatchIndex(query)
+	if match == nil {
+		return query, ""
+	}
+	return strings.TrimSpace(strings.TrimSpace(query[:match[0]]) + " " + strings.TrimSpace(query[match[1]:])), query[match[2]:match[3]]
+}
+
+// getReplyMessageLink returns the link to the message that a message replies to, or an empty string if the message
+// isn't a reply
+func getReplyMessageLink(message *discordgo.Message) string {
+	if message.MessageReference == nil || len(message.MessageReference.MessageID) == 0 {
+		return ""
+	}
+	guildID := message.MessageReference.GuildID
+	if len(guildID) == 0 {
+		guildID = message.GuildID
+	}
+	return generateMessageLink(guildID, message.MessageReference.ChannelID, message.MessageReference.MessageID)
+}
+
+// parseReminderTime parses the time at the beginning of s in the timezone of a user and returns the rest of s
+func parseReminderTime(ctx context.Context, store database.Store, userID, s string) (time.Time, string, error) {
+	now := time.Now().In(getUserLocation(ctx, store, userID))
+	when, rest, err := format.ParseTime(s, now)
+	if err != nil {
+		return when, s, i18n.Errorf("Invalid time format: ```%s```\n:information_source: _Try something like `45m`, `5h30m`, `7d`, `at 15:30`, `tomorrow at 8am`, `friday 9am` or `2030-11-03 09:00`._", err)
+	}
+	if duration := when.Sub(now); duration < limits.MinimumReminderDuration || duration > limits.MaximumReminderDuration {
+		return when, s, i18n.Errorf("Reminder must be due in between %s and %s from now", limits.MinimumReminderDuration, limits.MaximumReminderDuration)
+	}
+	return when, rest, nil
+}
+
+// parseRepeatClause parses the optional "every INTERVAL [until YYYY-MM-DD | N times]" clause at the beginning of s,
+// sets the repeat fields of the reminder accordingly and returns what's left of s.
+// If s doesn't start with "every", s is returned as is.
+func parseRepeatClause(s string, reminder *core.Reminder) (string, error) {
+	keyword, rest := nextArgument(s)
+	if strings.ToLower(keyword) != "every" {
+		return s, nil
+	}
+	interval, _, rest, err := format.ParsePeriodPrefix(rest)
+	if err != nil {
+		return s, err
+	}
+	// Months don't have a fixed length, so the interval is measured from the first occurrence of the reminder
+	if length := interval.AddTo(reminder.Time).Sub(reminder.Time); length < limits.MinimumRepeatInterval || length > limits.MaximumReminderDuration {
+		return s, i18n.Errorf("interval must be between %s and %s", limits.MinimumRepeatInterval, limits.MaximumReminderDuration)
+	}
+	reminder.RepeatInterval = interval.Duration
+	reminder.RepeatIntervalMonths = interval.Months
+	argument, restAfterEndCondition := nextArgument(rest)
+	if strings.ToLower(argument) == "until" {
+		dateArgument, restAfterDate := nextArgument(restAfterEndCondition)
+		date, err := time.ParseInLocation("2006-01-02", dateArgument, reminder.Time.Location())
+		if err != nil {
+			return s, i18n.Errorf("end date must have the format YYYY-MM-DD")
+		}
+		// The end date is inclusive, so the reminder may still be delivered on that day
+		reminder.RepeatUntil = date.AddDate(0, 0, 1).Add(-time.Second)
+		if reminder.RepeatUntil.Before(reminder.Time) {
+			return s, i18n.Errorf("end date must be after the first occurrence of the reminder")
+		}
+		return restAfterDate, nil
+	}
+	if occurrences, err := strconv.Atoi(argument); err == nil {
+		if keyword, restAfterOccurrences := nextArgument(restAfterEndCondition); strings.ToLower(keyword) == "times" {
+			if occurrences < 1 {
+				return s, i18n.Errorf("number of occurrences must be at least 1")
+			}
+			reminder.RepeatOccurrences = occurrences
+			return restAfterOccurrences, nil
+		}
+	}
+	return rest, nil
+}
+
+// nextArgument returns the first whitespace-separated argument of s as well as everything that comes after it
+func nextArgument(s string) (string, string) {
+	s = strings.TrimLeft(s, " \t\n")
+	if index := strings.IndexAny(s, " \t\n"); index != -1 {
+		return s[:index], s[index:]
+	}
+	return s, ""
+}
+
+func HandleListReminders(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate) {
 	directMessageChannel, err := bot.UserChannelCreate(message.Author.ID)
 	if err != nil {
 		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
-		log.Println("[discord][handleReminderListPage] Failed to open direct message:", err.Error())
+		slog.ErrorContext(ctx, "[discord][handleReminderListPage] Failed to open direct message", "error", err)
+		return
+	}
+	language := getLanguage(ctx, store, message.Author.ID, message.GuildID)
+	msg, numberOfPages, err := createReminderListMessageEmbed(ctx, store, language, directMessageChannel.ID, message.Author.ID, 1)
+	if err != nil {
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		slog.ErrorContext(ctx, "[discord][HandleListReminders] Failed to create embed message", "error", err)
+		return
+	}
+	_, err = bot.ChannelMessageSendComplex(directMessageChannel.ID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{msg},
+		Components: generateReminderListComponents(language, 1, numberOfPages),
+	})
+	if err != nil {
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		slog.ErrorContext(ctx, "[discord][HandleListReminders] Failed to send message", "error", err)
+		return
+	}
+	_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiSuccess)
+}
+
+func HandleTimezone(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, query string) {
+	settings, err := store.GetUserSettings(ctx, message.Author.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleTimezone] Failed to retrieve user settings", "user_id", message.Author.ID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	language := getLanguage(ctx, store, message.Author.ID, message.GuildID)
+	if len(query) == 0 {
+		commandPrefix := getCommandPrefix(ctx, store, message.GuildID)
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("Your timezone is currently `%s`.\n**Usage:**\n```%sTimezone AREA/CITY```**Where:**\n- `AREA/CITY` is the name of a timezone from the [tz database](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones), e.g. `America/Montreal` or `Europe/Paris`\n\n:information_source: _Your timezone is used to interpret absolute times like `at 15:30` and to display the time at which your reminders are due._", settings.Location().String(), commandPrefix), message.Reference())
+		return
+	}
+	location, err := time.LoadLocation(query)
+	if err != nil || query == "Local" {
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		_, err = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("Unknown timezone `%s`\n:information_source: _Try something like `America/Montreal`, `Europe/Paris` or `UTC`. The full list of timezones is available [here](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones)._", query), message.Reference())
+		if err != nil {
+			slog.WarnContext(ctx, "[discord][HandleTimezone] Failed to reply to message", "error", err)
+		}
+		return
+	}
+	if settings == nil {
+		settings = &core.UserSettings{UserID: message.Author.ID}
+	}
+	settings.Timezone = location.String()
+	if err = store.UpsertUserSettings(ctx, settings); err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleTimezone] Failed to update user settings", "user_id", message.Author.ID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("Your timezone has been set to `%s`, where it is currently %s.", settings.Timezone, time.Now().In(location).Format(language.Translate("Monday 15:04"))), message.Reference())
+	_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiSuccess)
+}
+
+// HandleBlock blocks or unblocks the users mentioned in a message, which prevents them from reminding the message's author
+func HandleBlock(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, block bool) {
+	if len(message.Mentions) == 0 {
+		language := getLanguage(ctx, store, message.Author.ID, message.GuildID)
+		commandPrefix := getCommandPrefix(ctx, store, message.GuildID)
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("**Usage:**\n```%sBlock @USER\n%sUnblock @USER```Blocked users can't create reminders for you.", commandPrefix, commandPrefix), message.Reference())
+		return
+	}
+	for _, user := range message.Mentions {
+		var err error
+		if block {
+			err = store.BlockUser(ctx, message.Author.ID, user.ID)
+		} else {
+			err = store.UnblockUser(ctx, message.Author.ID, user.ID)
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "[discord][HandleBlock] Failed to update blocked users", "user_id", message.Author.ID, "error", err)
+			_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+			return
+		}
+	}
+	_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiSuccess)
+}
+
+// HandleOptOut disables or enables reminders created by other users for the message's author
+func HandleOptOut(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, optOut bool) {
+	settings, err := store.GetUserSettings(ctx, message.Author.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleOptOut] Failed to retrieve user settings", "user_id", message.Author.ID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	if settings == nil {
+		settings = &core.UserSettings{UserID: message.Author.ID}
+	}
+	settings.RemindersFromOthersDisabled = optOut
+	if err = store.UpsertUserSettings(ctx, settings); err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleOptOut] Failed to update user settings", "user_id", message.Author.ID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	language := getLanguage(ctx, store, message.Author.ID, message.GuildID)
+	commandPrefix := getCommandPrefix(ctx, store, message.GuildID)
+	if optOut {
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("Other users can no longer create reminders for you. Use `%sOptIn` to allow them again.", commandPrefix), message.Reference())
+	} else {
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("Other users can now create reminders for you, unless you blocked them. Use `%sOptOut` to prevent them from doing so.", commandPrefix), message.Reference())
+	}
+	_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiSuccess)
+}
+
+// HandleRemindPermission configures which role, in addition to members who can manage messages, is allowed to remind
+// other users and roles in a guild. Only members who can manage the guild may use it.
+func HandleRemindPermission(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, query string) {
+	language := getLanguage(ctx, store, message.Author.ID, message.GuildID)
+	if !checkManageGuildPermission(bot, language, message) {
 		return
 	}
-	msg, numberOfPages, err := createReminderListMessageEmbed(directMessageChannel.ID, message.Author.ID, 1)
+	settings, err := store.GetGuildSettings(ctx, message.GuildID)
 	if err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleRemindPermission] Failed to retrieve guild settings", "guild_id", message.GuildID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	if settings == nil {
+		settings = &core.GuildSettings{GuildID: message.GuildID}
+	}
+	switch {
+	case len(message.MentionRoles) > 0:
+		settings.RemindOthersRoleID = message.MentionRoles[0]
+	case message.MentionEveryone || strings.EqualFold(strings.TrimPrefix(query, "@"), "everyone"):
+		// The ID of the @everyone role is the ID of the guild
+		settings.RemindOthersRoleID = message.GuildID
+	case strings.EqualFold(query, "reset"):
+		settings.RemindOthersRoleID = ""
+	default:
+		commandPrefix := getCommandPrefix(ctx, store, message.GuildID)
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("**Usage:**\n```%sRemindPermission @ROLE | everyone | reset```Members who can manage messages can always remind other users and roles. This command allows members with another role, or everyone, to do so as well.", commandPrefix), message.Reference())
+		return
+	}
+	if err = store.UpsertGuildSettings(ctx, settings); err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleRemindPermission] Failed to update guild settings", "guild_id", message.GuildID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiSuccess)
+}
+
+// HandleLanguage configures the language in which the bot responds to the message's author, or to every member of a
+// guild who did not select a language themselves. Only members who can manage the guild may configure the latter.
+func HandleLanguage(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, query string) {
+	language := getLanguage(ctx, store, message.Author.ID, message.GuildID)
+	argument, rest := nextArgument(query)
+	if strings.EqualFold(argument, "server") {
+		handleGuildLanguage(ctx, bot, store, message, language, strings.TrimSpace(rest))
+		return
+	}
+	settings, err := store.GetUserSettings(ctx, message.Author.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleLanguage] Failed to retrieve user settings", "user_id", message.Author.ID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	if settings == nil {
+		settings = &core.UserSettings{UserID: message.Author.ID}
+	}
+	switch {
+	case len(query) == 0:
+		commandPrefix := getCommandPrefix(ctx, store, message.GuildID)
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("I am currently responding to you in %s.\n**Usage:**\n```%sLanguage LANGUAGE | reset\n%sLanguage server LANGUAGE | reset```**Where:**\n- `LANGUAGE` is one of %s\n\n:information_source: _The language of the server is used for members who did not select a language themselves, and can only be changed by members who can manage the server._", language.Name(), commandPrefix, commandPrefix, generateLanguageList()), message.Reference())
+		return
+	case strings.EqualFold(query, "reset"):
+		settings.Language = ""
+	default:
+		selectedLanguage, ok := i18n.ParseLanguage(query)
+		if !ok {
+			_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+			_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("Unknown language `%s`, expected one of %s", query, generateLanguageList()), message.Reference())
+			return
+		}
+		settings.Language = string(selectedLanguage)
+	}
+	if err = store.UpsertUserSettings(ctx, settings); err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleLanguage] Failed to update user settings", "user_id", message.Author.ID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	language = getLanguage(ctx, store, message.Author.ID, message.GuildID)
+	_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("I will now respond to you in %s.", language.Name()), message.Reference())
+	_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiSuccess)
+}
+
+// handleGuildLanguage configures the language used for the members of a guild who did not select a language
+func handleGuildLanguage(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, language i18n.Language, query string) {
+	if !checkManageGuildPermission(bot, language, message) {
+		return
+	}
+	settings, err := store.GetGuildSettings(ctx, message.GuildID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][handleGuildLanguage] Failed to retrieve guild settings", "guild_id", message.GuildID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	if settings == nil {
+		settings = &core.GuildSettings{GuildID: message.GuildID}
+	}
+	if strings.EqualFold(query, "reset") {
+		settings.Language = ""
+	} else {
+		selectedLanguage, ok := i18n.ParseLanguage(query)
+		if !ok {
+			_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+			_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Sprintf("Unknown language `%s`, expected one of %s", query, generateLanguageList()), message.Reference())
+			return
+		}
+		settings.Language = string(selectedLanguage)
+	}
+	if err = store.UpsertGuildSettings(ctx, settings); err != nil {
+		slog.ErrorContext(ctx, "[discord][handleGuildLanguage] Failed to update guild settings", "guild_id", message.GuildID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiSuccess)
+}
+
+// checkManageGuildPermission returns whether the author of a message sent in a guild can manage that guild, and lets
+// them know why the command can't be used otherwise
+func checkManageGuildPermission(bot Client, language i18n.Language, message *discordgo.MessageCreate) bool {
+	if len(message.GuildID) == 0 {
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Translate("This command can only be used in a server."), message.Reference())
+		return false
+	}
+	permissions, err := bot.UserChannelPermissions(message.Author.ID, message.ChannelID)
+	if err != nil || permissions&discordgo.PermissionManageGuild == 0 {
 		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
-		log.Println("[discord][HandleListReminders] Failed to create embed message:", err.Error())
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Translate("You must have the Manage Server permission to use this command."), message.Reference())
+		return false
+	}
+	return true
+}
+
+// generateLanguageList generates the list of the codes of the supported languages, e.g. "`en` (English), `fr` (Français)"
+func generateLanguageList() string {
+	list := make([]string, 0, len(i18n.Languages))
+	for _, language := range i18n.Languages {
+		list = append(list, "`"+string(language)+"` ("+language.Name()+")")
+	}
+	return strings.Join(list, ", ")
+}
+
+// errInvalidConfigurationKey is returned by applyGuildConfiguration when the key to configure does not exist
+var errInvalidConfigurationKey = errors.New("invalid configuration key")
+
+// HandleConfig shows or changes the configuration of the bot in a guild. Only members who can manage the guild may use it.
+func HandleConfig(ctx context.Context, bot Client, store database.Store, message *discordgo.MessageCreate, query string) {
+	language := getLanguage(ctx, store, message.Author.ID, message.GuildID)
+	if !checkManageGuildPermission(bot, language, message) {
 		return
 	}
-	messageSent, err := bot.ChannelMessageSendEmbed(directMessageChannel.ID, msg)
+	settings, err := store.GetGuildSettings(ctx, message.GuildID)
 	if err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleConfig] Failed to retrieve guild settings", "guild_id", message.GuildID, "error", err)
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		return
+	}
+	if settings == nil {
+		settings = &core.GuildSettings{GuildID: message.GuildID}
+	}
+	key, value := nextArgument(query)
+	if len(key) == 0 {
+		_, _ = bot.ChannelMessageSendReply(message.ChannelID, generateGuildConfigurationDescription(language, settings)+"\n"+generateConfigUsage(language, settings), message.Reference())
+		return
+	}
+	if err = applyGuildConfiguration(bot, settings, strings.ToLower(key), strings.TrimSpace(value)); err != nil {
+		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
+		if errors.Is(err, errInvalidConfigurationKey) {
+			_, _ = bot.ChannelMessageSendReply(message.ChannelID, generateConfigUsage(language, settings), message.Reference())
+		} else {
+			_, _ = bot.ChannelMessageSendReply(message.ChannelID, language.Error(err), message.Reference())
+		}
+		return
+	}
+	if err = store.UpsertGuildSettings(ctx, settings); err != nil {
+		slog.ErrorContext(ctx, "[discord][HandleConfig] Failed to update guild settings", "guild_id", message.GuildID, "error", err)
 		_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiError)
-		log.Println("[discord][HandleListReminders] Failed to send message:", err.Error())
 		return
 	}
 	_ = bot.MessageReactionAdd(message.ChannelID, message.ID, EmojiSuccess)
-	_ = bot.MessageReactionAdd(messageSent.ChannelID, messageSent.ID, EmojiPageOne)
-	if numberOfPages >= 2 {
-		_ = bot.MessageReactionAdd(messageSent.ChannelID, messageSent.ID, EmojiPageTwo)
+}
+
+// applyGuildConfiguration sets the value of a configuration key in the settings of a guild, or resets it to its
+// default if the value is "reset"
+func applyGuildConfiguration(bot Client, settings *core.GuildSettings, key, value string) error {
+	reset := strings.EqualFold(value, "reset")
+	switch key {
+	case "prefix":
+		if reset {
+			settings.CommandPrefix = ""
+		} else if len(value) == 0 || len(value) > MaximumCommandPrefixLength || strings.ContainsAny(value, " \t\n") {
+			return i18n.Errorf("The prefix must have between 1 and %d characters, without spaces", MaximumCommandPrefixLength)
+		} else {
+			settings.CommandPrefix = value
+		}
+	case "reaction-duration":
+		if reset {
+			settings.ReactionReminderDuration = 0
+			return nil
+		}
+		duration, err := format.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		if duration < limits.MinimumReminderDuration || duration > limits.MaximumReminderDuration {
+			return i18n.Errorf("Reminder must be due in between %s and %s from now", limits.MinimumReminderDuration, limits.MaximumReminderDuration)
+		}
+		settings.ReactionReminderDuration = duration
+	case "max-reminders":
+		if reset {
+			settings.MaximumRemindersPerUser = 0
+			return nil
+		}
+		maximum, err := strconv.Atoi(value)
+		if err != nil || maximum < 1 || maximum > limits.MaximumConfigurableRemindersPerUser {
+			return i18n.Errorf("The maximum number of reminders per user must be between 1 and %d", limits.MaximumConfigurableRemindersPerUser)
+		}
+		settings.MaximumRemindersPerUser = maximum
+	case "channels":
+		if reset {
+			settings.AllowedChannelIDs = nil
+			return nil
+		}
+		var channelIDs []string
+		for _, match := range channelArgumentRegex.FindAllStringSubmatch(value, -1) {
+			if channel, err := bot.Channel(match[1]); err != nil || channel.GuildID != settings.GuildID {
+				return i18n.Errorf("Channel <#%s> must be in this server", match[1])
+			}
+			channelIDs = append(channelIDs, match[1])
+		}
+		if len(channelIDs) == 0 {
+			return errInvalidConfigurationKey
+		}
+		settings.AllowedChannelIDs = channelIDs
+	case "channel-delivery":
+		switch strings.ToLower(value) {
+		case "on", "reset":
+			settings.InChannelDeliveryDisabled = false
+		case "off":
+			settings.InChannelDeliveryDisabled = true
+		default:
+			return errInvalidConfigurationKey
+		}
+	case "language":
+		if reset {
+			settings.Language = ""
+			return nil
+		}
+		language, ok := i18n.ParseLanguage(value)
+		if !ok {
+			return i18n.Errorf("Unknown language `%s`, expected one of %s", value, generateLanguageList())
+		}
+		settings.Language = string(language)
+	default:
+		return errInvalidConfigurationKey
 	}
-	if numberOfPages >= 3 {
-		_ = bot.MessageReactionAdd(messageSent.ChannelID, messageSent.ID, EmojiPageThree)
+	return nil
+}
+
+// generateGuildConfigurationDescription generates the description of the configuration of the bot in a guild
+func generateGuildConfigurationDescription(language i18n.Language, settings *core.GuildSettings) string {
+	channels := language.Translate("all channels")
+	if len(settings.AllowedChannelIDs) > 0 {
+		channels = "<#" + strings.Join(settings.AllowedChannelIDs, ">, <#") + ">"
 	}
-	if numberOfPages >= 4 {
-		_ = bot.MessageReactionAdd(messageSent.ChannelID, messageSent.ID, EmojiPageFour)
+	channelDelivery := "on"
+	if !settings.AllowsInChannelDelivery() {
+		channelDelivery = "off"
 	}
-	if numberOfPages >= 5 {
-		_ = bot.MessageReactionAdd(messageSent.ChannelID, messageSent.ID, EmojiPageFive)
+	guildLanguage := language.Translate("not set")
+	if selectedLanguage, ok := i18n.ParseLanguage(settings.Language); ok {
+		guildLanguage = selectedLanguage.Name()
 	}
+	return language.Sprintf(
+		"**Configuration of this server:**\n- `prefix`: `%s`\n- `reaction-duration`: %s\n- `max-reminders`: %d\n- `channels`: %s\n- `channel-delivery`: %s\n- `language`: %s",
+		settings.GetCommandPrefix(botCommandPrefix),
+		format.PrettyDurationIn(language, settings.GetReactionReminderDuration(limits.DefaultReactionReminderDuration)),
+		min(settings.GetMaximumRemindersPerUser(limits.MaximumNumberOfRemindersPerUser), limits.MaximumConfigurableRemindersPerUser),
+		channels,
+		channelDelivery,
+		guildLanguage,
+	)
+}
+
+// generateConfigUsage generates the usage of the Config command
+func generateConfigUsage(language i18n.Language, settings *core.GuildSettings) string {
+	return language.Sprintf("**Usage:**\n```%[1]sConfig prefix PREFIX | reset\n%[1]sConfig reaction-duration DURATION | reset\n%[1]sConfig max-reminders NUMBER | reset\n%[1]sConfig channels #CHANNEL... | reset\n%[1]sConfig channel-delivery on | off\n%[1]sConfig language LANGUAGE | reset```**Where:**\n- `prefix` is the prefix of my commands in this server\n- `reaction-duration` is in how long reminders created by reacting to a message are due\n- `max-reminders` is the maximum number of reminders a member can have\n- `channels` are the only channels in which I can be used, but `%[1]sConfig` can be used anywhere\n- `channel-delivery` is whether reminders can be delivered in a channel rather than by direct message", settings.GetCommandPrefix(botCommandPrefix))
 }
@@ -0,0 +1,199 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestExtractChannelOption(t *testing.T) {
+	scenarios := []struct {
+		Name              string
+		Query             string
+		ExpectedQuery     string
+		ExpectedChannelID string
+	}{
+		{
+			Name:              "no-option",
+			Query:             "2h deploy freeze",
+			ExpectedQuery:     "2h deploy freeze",
+			ExpectedChannelID: "",
+		},
+		{
+			Name:              "channel-mention-in-the-middle",
+			Query:             "at 16:00 --channel <#123456> deploy freeze",
+			ExpectedQuery:     "at 16:00 deploy freeze",
+			ExpectedChannelID: "123456",
+		},
+		{
+			Name:              "channel-id-at-the-beginning",
+			Query:             "--channel 123456 2h",
+			ExpectedQuery:     "2h",
+			ExpectedChannelID: "123456",
+		},
+		{
+			Name:              "channel-mention-at-the-end",
+			Query:             "2h deploy freeze --CHANNEL <#123456>",
+			ExpectedQuery:     "2h deploy freeze",
+			ExpectedChannelID: "123456",
+		},
+		{
+			Name:              "option-without-channel",
+			Query:             "2h --channel general",
+			ExpectedQuery:     "2h --channel general",
+			ExpectedChannelID: "",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			query, channelID := extractChannelOption(scenario.Query)
+			if query != scenario.ExpectedQuery {
+				t.Errorf("expected query '%s', got '%s'", scenario.ExpectedQuery, query)
+			}
+			if channelID != scenario.ExpectedChannelID {
+				t.Errorf("expected channel ID '%s', got '%s'", scenario.ExpectedChannelID, channelID)
+			}
+		})
+	}
+}
+
+func TestExtractMessageLink(t *testing.T) {
+	scenarios := []struct {
+		Name                string
+		Query               string
+		ExpectedQuery       string
+		ExpectedMessageLink string
+	}{
+		{
+			Name:                "no-link",
+			Query:               "2h review the PR",
+			ExpectedQuery:       "2h review the PR",
+			ExpectedMessageLink: "",
+		},
+		{
+			Name:                "link-after-time",
+			Query:               "2h https://discord.com/channels/1/2/3 review the PR",
+			ExpectedQuery:       "2h review the PR",
+			ExpectedMessageLink: "https://discord.com/channels/1/2/3",
+		},
+		{
+			Name:                "link-without-embed-at-the-end",
+			Query:               "tomorrow at 9am review the PR <https://ptb.discord.com/channels/@me/2/3>",
+			ExpectedQuery:       "tomorrow at 9am review the PR",
+			ExpectedMessageLink: "https://ptb.discord.com/channels/@me/2/3",
+		},
+		{
+			Name:                "channel-link",
+			Query:               "2h https://discord.com/channels/1/2",
+			ExpectedQuery:       "2h https://discord.com/channels/1/2",
+			ExpectedMessageLink: "",
+		},
+		{
+			Name:                "link-inside-other-text",
+			Query:               "2h see(https://discord.com/channels/1/2/3)",
+			ExpectedQuery:       "2h see(https://discord.com/channels/1/2/3)",
+			ExpectedMessageLink: "",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			query, messageLink := extractMessageLink(scenario.Query)
+			if query != scenario.ExpectedQuery {
+				t.Errorf("expected query '%s', got '%s'", scenario.ExpectedQuery, query)
+			}
+			if messageLink != scenario.ExpectedMessageLink {
+				t.Errorf("expected message link '%s', got '%s'", scenario.ExpectedMessageLink, messageLink)
+			}
+		})
+	}
+}
+
+func TestGetReplyMessageLink(t *testing.T) {
+	scenarios := []struct {
+		Name                string
+		Message             *discordgo.Message
+		ExpectedMessageLink string
+	}{
+		{
+			Name:                "not-a-reply",
+			Message:             &discordgo.Message{GuildID: "1", ChannelID: "2", ID: "3"},
+			ExpectedMessageLink: "",
+		},
+		{
+			Name:                "reply-in-same-guild",
+			Message:             &discordgo.Message{GuildID: "1", ChannelID: "2", ID: "3", MessageReference: &discordgo.MessageReference{ChannelID: "2", MessageID: "4"}},
+			ExpectedMessageLink: generateMessageLink("1", "2", "4"),
+		},
+		{
+			Name:                "reference-to-different-guild",
+			Message:             &discordgo.Message{GuildID: "1", ChannelID: "2", ID: "3", MessageReference: &discordgo.MessageReference{GuildID: "5", ChannelID: "6", MessageID: "7"}},
+			ExpectedMessageLink: generateMessageLink("5", "6", "7"),
+		},
+		{
+			Name:                "reply-in-direct-message",
+			Message:             &discordgo.Message{ChannelID: "2", ID: "3", MessageReference: &discordgo.MessageReference{ChannelID: "2", MessageID: "4"}},
+			ExpectedMessageLink: generateMessageLink("", "2", "4"),
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if messageLink := getReplyMessageLink(scenario.Message); messageLink != scenario.ExpectedMessageLink {
+				t.Errorf("expected message link '%s', got '%s'", scenario.ExpectedMessageLink, messageLink)
+			}
+		})
+	}
+}
+
+func TestParseRepeatClause(t *testing.T) {
+	scenarios := []struct {
+		Name                   string
+		Query                  string
+		ExpectedRest           string
+		ExpectedInterval       time.Duration
+		ExpectedIntervalMonths int
+		ExpectedErr            bool
+	}{
+		{
+			Name:         "no-clause",
+			Query:        "renew the contract",
+			ExpectedRest: "renew the contract",
+		},
+		{
+			Name:             "multi-token-interval",
+			Query:            "every 2 weeks water the plants",
+			ExpectedRest:     " water the plants",
+			ExpectedInterval: 14 * 24 * time.Hour,
+		},
+		{
+			Name:                   "months",
+			Query:                  "every 3 months rotate certificates",
+			ExpectedRest:           " rotate certificates",
+			ExpectedIntervalMonths: 3,
+		},
+		{
+			Name:        "interval-too-short",
+			Query:       "every 5m",
+			ExpectedErr: true,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			reminder := &core.Reminder{Time: time.Date(2027, 1, 31, 9, 0, 0, 0, time.UTC)}
+			rest, err := parseRepeatClause(scenario.Query, reminder)
+			if (err != nil) != scenario.ExpectedErr {
+				t.Fatalf("expected error to be %v, got %v", scenario.ExpectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if rest != scenario.ExpectedRest {
+				t.Errorf("expected rest '%s', got '%s'", scenario.ExpectedRest, rest)
+			}
+			if reminder.RepeatInterval != scenario.ExpectedInterval || reminder.RepeatIntervalMonths != scenario.ExpectedIntervalMonths {
+				t.Errorf("expected interval of %s and %d months, got %s and %d months", scenario.ExpectedInterval, scenario.ExpectedIntervalMonths, reminder.RepeatInterval, reminder.RepeatIntervalMonths)
+			}
+		})
+	}
+}
@@ -1,102 +1,123 @@
 package discord
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"slices"
 	"time"
 
+	"github.com/TwiN/discord-reminder-bot/core"
 	"github.com/TwiN/discord-reminder-bot/database"
+	"github.com/TwiN/discord-reminder-bot/logging"
+	"github.com/TwiN/discord-reminder-bot/metrics"
 	"github.com/bwmarrin/discordgo"
 )
 
-func HandleReactionAdd(bot *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
-	handleReaction(bot, reaction.MessageReaction, false)
+func HandleReactionAdd(bot Client, store database.Store, reaction *discordgo.MessageReactionAdd) {
+	ctx := logging.WithCorrelationID(context.Background())
+	handleReaction(ctx, bot, store, reaction.MessageReaction, false)
 }
 
-func HandleReactionRemove(bot *discordgo.Session, reaction *discordgo.MessageReactionRemove) {
-	handleReaction(bot, reaction.MessageReaction, true)
+func HandleReactionRemove(bot Client, store database.Store, reaction *discordgo.MessageReactionRemove) {
+	ctx := logging.WithCorrelationID(context.Background())
+	handleReaction(ctx, bot, store, reaction.MessageReaction, true)
 }
 
-func handleReaction(bot *discordgo.Session, reaction *discordgo.MessageReaction, remove bool) {
+func handleReaction(ctx context.Context, bot Client, store database.Store, reaction *discordgo.MessageReaction, remove bool) {
 	// Make sure that the reaction is on one of the bot's messages
-	if reaction.UserID == bot.State.User.ID {
+	if reaction.UserID == bot.BotUser().ID {
 		return
 	}
-	switch reaction.Emoji.Name {
-	case EmojiCreateReminder, EmojiCreateReminderAlt1, EmojiCreateReminderAlt2:
-		// Create a new reminder when a user reacts with EmojiCreateReminder or EmojiCreateReminderAlt1
+	if slices.Contains(reminderEmojis, reaction.Emoji.Name) {
+		// Create a new reminder when a user reacts with one of the reminder emojis
 		if !remove {
-			handleReactionCreateReminder(bot, reaction)
+			handleReactionCreateReminder(ctx, bot, store, reaction)
 		}
+		return
+	}
+	switch reaction.Emoji.Name {
 	case EmojiPageOne, EmojiPageTwo, EmojiPageThree, EmojiPageFour, EmojiPageFive:
 		// Navigate page of reminders
-		handleReactionListReminders(bot, reaction)
+		handleReactionListReminders(ctx, bot, store, reaction)
 	case EmojiIncreaseDuration, EmojiDecreaseDuration, EmojiDeleteReminder, EmojiRefreshDuration:
 		// Modify an existing reminder
-		handleReactionModifyReminder(bot, reaction)
+		handleReactionModifyReminder(ctx, bot, store, reaction)
 	}
 }
 
-func handleReactionCreateReminder(bot *discordgo.Session, reaction *discordgo.MessageReaction) {
-	_, err := createReminder(bot, reaction.UserID, reaction.GuildID, reaction.ChannelID, reaction.MessageID, "", time.Now().Add(8*time.Hour))
+func handleReactionCreateReminder(ctx context.Context, bot Client, store database.Store, reaction *discordgo.MessageReaction) {
+	guildSettings := getGuildSettings(ctx, store, reaction.GuildID)
+	if len(reaction.GuildID) > 0 && !isChannelAllowed(bot, guildSettings, reaction.ChannelID) {
+		return
+	}
+	err := createReminder(ctx, bot, store, reaction.GuildID, metrics.SourceReaction, &core.Reminder{
+		CreatorID:   reaction.UserID,
+		UserID:      reaction.UserID,
+		MessageLink: generateMessageLink(reaction.GuildID, reaction.ChannelID, reaction.MessageID),
+		Time:        time.Now().In(getUserLocation(ctx, store, reaction.UserID)).Add(guildSettings.GetReactionReminderDuration(limits.DefaultReactionReminderDuration)),
+	})
 	if err != nil {
-		log.Printf("[discord][HandleReactionAdd] Failed to create reminder: %s", err.Error())
+		slog.ErrorContext(ctx, "[discord][HandleReactionAdd] Failed to create reminder", "error", err)
 		return
 	}
 }
 
-func handleReactionListReminders(bot *discordgo.Session, reaction *discordgo.MessageReaction) {
+func handleReactionListReminders(ctx context.Context, bot Client, store database.Store, reaction *discordgo.MessageReaction) {
 	switch reaction.Emoji.Name {
 	case EmojiPageOne:
-		updateExistingReminderListMessage(bot, reaction.UserID, reaction.ChannelID, reaction.MessageID, 1)
+		updateExistingReminderListMessage(ctx, bot, store, reaction.UserID, reaction.ChannelID, reaction.MessageID, 1)
 	case EmojiPageTwo:
-		updateExistingReminderListMessage(bot, reaction.UserID, reaction.ChannelID, reaction.MessageID, 2)
+		updateExistingReminderListMessage(ctx, bot, store, reaction.UserID, reaction.ChannelID, reaction.MessageID, 2)
 	case EmojiPageThree:
-		updateExistingReminderListMessage(bot, reaction.UserID, reaction.ChannelID, reaction.MessageID, 3)
+		updateExistingReminderListMessage(ctx, bot, store, reaction.UserID, reaction.ChannelID, reaction.MessageID, 3)
 	case EmojiPageFour:
-		updateExistingReminderListMessage(bot, reaction.UserID, reaction.ChannelID, reaction.MessageID, 4)
+		updateExistingReminderListMessage(ctx, bot, store, reaction.UserID, reaction.ChannelID, reaction.MessageID, 4)
 	case EmojiPageFive:
-		updateExistingReminderListMessage(bot, reaction.UserID, reaction.ChannelID, reaction.MessageID, 5)
+		updateExistingReminderListMessage(ctx, bot, store, reaction.UserID, reaction.ChannelID, reaction.MessageID, 5)
 	default:
 		return // not supported
 	}
 }
 
-func handleReactionModifyReminder(bot *discordgo.Session, reaction *discordgo.MessageReaction) {
+func handleReactionModifyReminder(ctx context.Context, bot Client, store database.Store, reaction *discordgo.MessageReaction) {
 	if channel, err := bot.Channel(reaction.ChannelID); err == nil && channel.Type != discordgo.ChannelTypeDM {
 		// Ignore reactions that do not come from DMs.
 		// While reminders can be created from reactions outside of DMs, all reactions are managed in DMs.
 		return
 	}
-	reminder, err := database.GetReminderByNotificationMessageID(reaction.MessageID)
+	reminder, err := store.GetReminderByNotificationMessageID(ctx, reaction.MessageID)
 	if err != nil {
-		log.Println("[discord][handleReactionModifyReminder] Failed to retrieve reminder by notification message id:", err.Error())
+		slog.ErrorContext(ctx, "[discord][handleReactionModifyReminder] Failed to retrieve reminder by notification message id", "error", err)
 		return
 	}
 	if reminder == nil {
 		// doesn't exist, so we'll ignore the message.
 		return
 	}
+	localizeReminder(ctx, store, reminder)
+	language := getReminderLanguage(ctx, store, reminder.CreatorID, reminder)
 	switch reaction.Emoji.Name {
 	case EmojiIncreaseDuration:
 		reminder.Time = reminder.Time.Add(time.Hour)
-		_ = database.UpdateReminder(reminder)
-		_, _ = updateExistingMessage(bot, reaction.ChannelID, reaction.MessageID, "", reminder.GenerateNotificationMessageContent())
+		_ = updateReminder(ctx, store, reminder)
+		_, _ = updateExistingMessage(bot, reaction.ChannelID, reaction.MessageID, "", reminder.GenerateNotificationMessageContent(language))
 	case EmojiDecreaseDuration:
 		// No need to worry about checking if we're under 0, just let it be naturally handled
 		reminder.Time = reminder.Time.Add(-time.Hour)
-		_ = database.UpdateReminder(reminder)
-		_, _ = updateExistingMessage(bot, reaction.ChannelID, reaction.MessageID, "", reminder.GenerateNotificationMessageContent())
+		_ = updateReminder(ctx, store, reminder)
+		_, _ = updateExistingMessage(bot, reaction.ChannelID, reaction.MessageID, "", reminder.GenerateNotificationMessageContent(language))
 	case EmojiRefreshDuration:
-		_, _ = updateExistingMessage(bot, reaction.ChannelID, reaction.MessageID, "", reminder.GenerateNotificationMessageContent())
+		_, _ = updateExistingMessage(bot, reaction.ChannelID, reaction.MessageID, "", reminder.GenerateNotificationMessageContent(language))
 	case EmojiDeleteReminder:
-		deleteReminder(bot, reaction.ChannelID, reminder)
+		deleteReminder(ctx, bot, store, reaction.ChannelID, reminder)
+		metrics.RemindersDeleted.Inc()
 	default:
 		return // not supported
 	}
 }
 
-func updateExistingReminderListMessage(bot *discordgo.Session, userID, channelID, messageID string, page int) {
-	msg, _, err := createReminderListMessageEmbed(channelID, userID, page)
+func updateExistingReminderListMessage(ctx context.Context, bot Client, store database.Store, userID, channelID, messageID string, page int) {
+	msg, _, err := createReminderListMessageEmbed(ctx, store, getLanguage(ctx, store, userID, ""), channelID, userID, page)
 	if err != nil {
 		return
 	}
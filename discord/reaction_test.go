@@ -0,0 +1,81 @@
+package discord
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/config"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestHandleReactionAdd_CreateAndModifyReminder(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	bot.AddChannel("10", "11")
+	message := bot.InjectMessage("10", "11", user, "meeting at 3pm")
+	bot.InjectReactionAdd("10", "11", message.ID, user.ID, reminderEmojis[0])
+	reminders, _ := store.GetRemindersByCreatorID(context.Background(), user.ID, 0, 10)
+	if len(reminders) != 1 {
+		t.Fatal("reacting to a message should've created a reminder, got", len(reminders), "reminders")
+	}
+	reminder := reminders[0]
+	if reminder.MessageLink != generateMessageLink("10", "11", message.ID) {
+		t.Error("reminder should've linked to the message reacted to, got", reminder.MessageLink)
+	}
+	if reminder.MessageSnapshot == nil || reminder.MessageSnapshot.Content != "meeting at 3pm" || reminder.MessageSnapshot.AuthorName != "user" {
+		t.Error("reminder should've had a snapshot of the message reacted to, got", reminder.MessageSnapshot)
+	}
+	directMessageChannelID := bot.DirectMessageChannelID(user.ID)
+	if notificationMessage := bot.Message(reminder.NotificationMessageID); notificationMessage == nil || notificationMessage.ChannelID != directMessageChannelID {
+		t.Fatal("notification message should've been sent to the user by direct message")
+	}
+	// Reactions on messages that aren't in a direct message channel are ignored
+	bot.InjectReactionAdd("10", "11", reminder.NotificationMessageID, user.ID, EmojiIncreaseDuration)
+	if unchangedReminder, _ := store.GetReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID); !unchangedReminder.Time.Equal(reminder.Time) {
+		t.Error("reminder should not have been modified by a reaction outside of a direct message channel")
+	}
+	bot.InjectReactionAdd("", directMessageChannelID, reminder.NotificationMessageID, user.ID, EmojiIncreaseDuration)
+	postponedReminder, _ := store.GetReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID)
+	if postponedReminder.Time.Sub(reminder.Time) != time.Hour {
+		t.Error("reminder should've been postponed by an hour, got", postponedReminder.Time.Sub(reminder.Time))
+	}
+	if description := bot.Message(reminder.NotificationMessageID).Embeds[0].Description; !strings.Contains(description, "in 8 hours") && !strings.Contains(description, "in 9 hours") {
+		t.Error("notification message should've been updated with the new time, got", description)
+	}
+	bot.InjectReactionAdd("", directMessageChannelID, reminder.NotificationMessageID, user.ID, EmojiDeleteReminder)
+	if deletedReminder, _ := store.GetReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID); deletedReminder != nil {
+		t.Error("reminder should've been deleted")
+	}
+	if description := bot.Message(reminder.NotificationMessageID).Embeds[0].Description; !strings.HasPrefix(description, "~~") {
+		t.Error("notification message should've been crossed out, got", description)
+	}
+}
+
+func TestHandleReactionAdd_IgnoresBot(t *testing.T) {
+	bot, store := newFakeBot(t)
+	bot.AddChannel("10", "11")
+	message := bot.InjectMessage("10", "11", &discordgo.User{ID: "2"}, "meeting at 3pm")
+	bot.InjectReactionAdd("10", "11", message.ID, bot.BotUser().ID, reminderEmojis[0])
+	if numberOfReminders, _ := store.CountReminders(context.Background()); numberOfReminders != 0 {
+		t.Error("reactions of the bot itself should've been ignored, got", numberOfReminders, "reminders")
+	}
+}
+
+func TestHandleReactionAdd_ConfiguredReminderEmojis(t *testing.T) {
+	cfg := config.Default()
+	cfg.ReminderEmojis = []string{"👀"}
+	bot, store := newFakeBotWithConfig(t, cfg)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	bot.AddChannel("10", "11")
+	message := bot.InjectMessage("10", "11", user, "meeting at 3pm")
+	bot.InjectReactionAdd("10", "11", message.ID, user.ID, config.DefaultReminderEmojis[0])
+	if numberOfReminders, _ := store.CountReminders(context.Background()); numberOfReminders != 0 {
+		t.Fatal("reacting with an emoji that isn't configured should not have created a reminder, got", numberOfReminders)
+	}
+	bot.InjectReactionAdd("10", "11", message.ID, user.ID, "👀")
+	if numberOfReminders, _ := store.CountReminders(context.Background()); numberOfReminders != 1 {
+		t.Error("reacting with a configured emoji should've created a reminder, got", numberOfReminders)
+	}
+}
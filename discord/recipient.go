@@ -0,0 +1,96 @@
+package discord
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/database"
+	"github.com/TwiN/discord-reminder-bot/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+var recipientMentionRegex = regexp.MustCompile(`^\s*<@(!|&)?(\d+)>`)
+
+// extractRecipient removes the mention of a user or a role at the beginning of a query and returns the rest of it
+func extractRecipient(query string) (rest, userID, roleID string) {
+	match := recipientMentionRegex.FindStringSubmatch(query)
+	if match == nil {
+		return query, "", ""
+	}
+	rest = query[len(match[0]):]
+	if match[1] == "&" {
+		return rest, "", match[2]
+	}
+	return rest, match[2], ""
+}
+
+// validateRecipient makes sure that the creator of a reminder is allowed to remind its recipient
+func validateRecipient(ctx context.Context, bot Client, store database.Store, guildID, channelID string, reminder *core.Reminder) error {
+	if !reminder.IsForSomeoneElse() {
+		return nil
+	}
+	if len(guildID) == 0 {
+		return i18n.Errorf("You can only remind other users and roles from a server")
+	}
+	permissions, err := bot.UserChannelPermissions(reminder.CreatorID, channelID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][validateRecipient] Failed to retrieve permissions", "user_id", reminder.CreatorID, "channel_id", channelID, "error", err)
+		return i18n.Errorf("Error: failed to retrieve your permissions")
+	}
+	if !canRemindOthers(ctx, bot, store, guildID, reminder.CreatorID, permissions) {
+		return i18n.Errorf("You are not allowed to remind other users and roles in this server")
+	}
+	if len(reminder.RoleID) > 0 {
+		role, err := bot.GuildRole(guildID, reminder.RoleID)
+		if err != nil {
+			return i18n.Errorf("Role <@&%s> does not exist in this server", reminder.RoleID)
+		}
+		if !role.Mentionable && permissions&discordgo.PermissionMentionEveryone == 0 {
+			return i18n.Errorf("You are not allowed to mention <@&%s>", reminder.RoleID)
+		}
+		return nil
+	}
+	member, err := bot.GuildMember(guildID, reminder.UserID)
+	if err != nil {
+		return i18n.Errorf("<@%s> is not a member of this server", reminder.UserID)
+	}
+	if member.User != nil && member.User.Bot {
+		return i18n.Errorf("Bots cannot be reminded")
+	}
+	settings, err := store.GetUserSettings(ctx, reminder.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][validateRecipient] Failed to retrieve user settings", "user_id", reminder.UserID, "error", err)
+		return i18n.Errorf("Error: failed to retrieve the settings of the user to remind")
+	}
+	blocked, err := store.IsUserBlocked(ctx, reminder.UserID, reminder.CreatorID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][validateRecipient] Failed to check whether user is blocked", "user_id", reminder.UserID, "blocked_user_id", reminder.CreatorID, "error", err)
+		return i18n.Errorf("Error: failed to retrieve the settings of the user to remind")
+	}
+	if blocked || (settings != nil && settings.RemindersFromOthersDisabled) {
+		return i18n.Errorf("<@%s> does not accept reminders from you", reminder.UserID)
+	}
+	return nil
+}
+
+// canRemindOthers returns whether a member of a guild is allowed to remind other users and roles
+func canRemindOthers(ctx context.Context, bot Client, store database.Store, guildID, userID string, permissions int64) bool {
+	if permissions&discordgo.PermissionManageMessages != 0 {
+		return true
+	}
+	settings, err := store.GetGuildSettings(ctx, guildID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][canRemindOthers] Failed to retrieve guild settings", "guild_id", guildID, "error", err)
+		return false
+	}
+	if settings == nil {
+		return false
+	}
+	member, err := bot.GuildMember(guildID, userID)
+	if err != nil {
+		return false
+	}
+	return settings.AllowsRemindingOthers(member.Roles)
+}
@@ -0,0 +1,56 @@
+package discord
+
+import "testing"
+
+func TestExtractRecipient(t *testing.T) {
+	scenarios := []struct {
+		Name           string
+		Query          string
+		ExpectedRest   string
+		ExpectedUserID string
+		ExpectedRoleID string
+	}{
+		{
+			Name:         "no-mention",
+			Query:        "30m check the canary",
+			ExpectedRest: "30m check the canary",
+		},
+		{
+			Name:           "user-mention",
+			Query:          "<@123> 30m check the canary",
+			ExpectedRest:   " 30m check the canary",
+			ExpectedUserID: "123",
+		},
+		{
+			Name:           "user-nickname-mention",
+			Query:          "<@!123> 30m",
+			ExpectedRest:   " 30m",
+			ExpectedUserID: "123",
+		},
+		{
+			Name:           "role-mention",
+			Query:          "<@&456> 30m check the canary",
+			ExpectedRest:   " 30m check the canary",
+			ExpectedRoleID: "456",
+		},
+		{
+			Name:         "mention-after-time",
+			Query:        "30m <@123> check the canary",
+			ExpectedRest: "30m <@123> check the canary",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			rest, userID, roleID := extractRecipient(scenario.Query)
+			if rest != scenario.ExpectedRest {
+				t.Errorf("expected rest '%s', got '%s'", scenario.ExpectedRest, rest)
+			}
+			if userID != scenario.ExpectedUserID {
+				t.Errorf("expected user ID '%s', got '%s'", scenario.ExpectedUserID, userID)
+			}
+			if roleID != scenario.ExpectedRoleID {
+				t.Errorf("expected role ID '%s', got '%s'", scenario.ExpectedRoleID, roleID)
+			}
+		})
+	}
+}
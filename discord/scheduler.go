@@ -0,0 +1,267 @@
+package discord
+
+import (
+	"container/heap"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+// scheduledReminder is an entry of the scheduler's queue
+type scheduledReminder struct {
+	NotificationMessageID string
+	Time                  time.Time
+}
+
+// reminderQueue is a min-heap of scheduled reminders, ordered by the time at which they are due
+type reminderQueue []scheduledReminder
+
+func (q reminderQueue) Len() int           { return len(q) }
+func (q reminderQueue) Less(i, j int) bool { return q[i].Time.Before(q[j].Time) }
+func (q reminderQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *reminderQueue) Push(x interface{}) {
+	*q = append(*q, x.(scheduledReminder))
+}
+
+func (q *reminderQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+// scheduler keeps the reminders that are due the soonest in memory and delivers each of them as soon as it is due.
+//
+// Because the queue only holds up to capacity reminders, the scheduler keeps track of the time of the last reminder
+// it loaded (the horizon). Reminders due after the horizon are not queued, and are instead picked up the next time the
+// scheduler reloads its queue from the database, which happens once no queued reminder is due before the horizon or
+// every refreshInterval. Postponed reminders don't count towards the capacity, so that reminders whose delivery keeps
+// failing can't prevent the ones due after them from being loaded.
+type scheduler struct {
+	capacity                    int
+	maximumConcurrentDeliveries int
+	refreshInterval             time.Duration
+
+	load    func(limit int) ([]*core.Reminder, error)
+	deliver func(notificationMessageID string)
+
+	mutex     sync.Mutex
+	queue     reminderQueue
+	scheduled map[string]time.Time // Source of truth of the queue; entries in the queue that do not match are stale
+	postponed map[string]time.Time // Reminders whose delivery was postponed, which must survive reloads
+	horizon   time.Time            // Time of the last reminder loaded, or zero if every reminder was loaded
+
+	wake chan struct{}
+
+	statusMutex             sync.Mutex // Separate from mutex, because mutex is held while reminders are being loaded
+	lastLoad                time.Time  // Last time reminders were loaded successfully, or zero if they never were
+	consecutiveLoadFailures int
+}
+
+func newScheduler(capacity, maximumConcurrentDeliveries int, refreshInterval time.Duration, load func(limit int) ([]*core.Reminder, error), deliver func(notificationMessageID string)) *scheduler {
+	return &scheduler{
+		capacity:                    capacity,
+		maximumConcurrentDeliveries: maximumConcurrentDeliveries,
+		refreshInterval:             refreshInterval,
+		load:                        load,
+		deliver:                     deliver,
+		scheduled:                   make(map[string]time.Time),
+		postponed:                   make(map[string]time.Time),
+		wake:                        make(chan struct{}, 1),
+	}
+}
+
+// Schedule adds a reminder to the queue, or moves it if it was already queued
+func (s *scheduler) Schedule(notificationMessageID string, when time.Time) {
+	s.mutex.Lock()
+	delete(s.postponed, notificationMessageID)
+	if !s.horizon.IsZero() && when.After(s.horizon) {
+		// The reminder will be loaded from the database once the scheduler gets closer to it
+		delete(s.scheduled, notificationMessageID)
+	} else {
+		s.scheduled[notificationMessageID] = when
+		heap.Push(&s.queue, scheduledReminder{NotificationMessageID: notificationMessageID, Time: when})
+	}
+	s.mutex.Unlock()
+	s.signal()
+}
+
+// Unschedule removes a reminder from the queue
+func (s *scheduler) Unschedule(notificationMessageID string) {
+	s.mutex.Lock()
+	delete(s.scheduled, notificationMessageID)
+	delete(s.postponed, notificationMessageID)
+	s.mutex.Unlock()
+	s.signal()
+}
+
+// Postpone delays the delivery of a reminder that is due until a given time, without changing the time at which the
+// reminder is due. Unlike reminders scheduled with Schedule, the postponed time is kept when the queue is reloaded.
+func (s *scheduler) Postpone(notificationMessageID string, until time.Time) {
+	s.mutex.Lock()
+	s.postponed[notificationMessageID] = until
+	s.scheduled[notificationMessageID] = until
+	heap.Push(&s.queue, scheduledReminder{NotificationMessageID: notificationMessageID, Time: until})
+	s.mutex.Unlock()
+	s.signal()
+}
+
+// signal wakes up the scheduler so that it can recompute when the next reminder is due
+func (s *scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+		// The scheduler already has a pending wake up signal
+	}
+}
+
+// reload replaces the queue by the reminders that are due the soonest according to the database
+func (s *scheduler) reload() error {
+	// The lock is held while loading reminders so that reminders scheduled in the meantime aren't lost
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	limit := s.capacity + len(s.postponed)
+	reminders, err := s.load(limit)
+	if err != nil {
+		return err
+	}
+	s.queue = make(reminderQueue, 0, len(reminders))
+	s.scheduled = make(map[string]time.Time, len(reminders))
+	for _, reminder := range reminders {
+		when := reminder.Time
+		if until, postponed := s.postponed[reminder.NotificationMessageID]; postponed && until.After(when) {
+			when = until
+		}
+		s.queue = append(s.queue, scheduledReminder{NotificationMessageID: reminder.NotificationMessageID, Time: when})
+		s.scheduled[reminder.NotificationMessageID] = when
+	}
+	heap.Init(&s.queue)
+	if len(reminders) >= limit {
+		s.horizon = reminders[len(reminders)-1].Time
+	} else {
+		s.horizon = time.Time{}
+	}
+	return nil
+}
+
+// recordLoad keeps track of the outcome of an attempt to load reminders from the database, and returns the number of
+// times in a row that loading reminders has failed
+func (s *scheduler) recordLoad(err error) int {
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+	if err != nil {
+		s.consecutiveLoadFailures++
+	} else {
+		s.lastLoad, s.consecutiveLoadFailures = time.Now(), 0
+	}
+	return s.consecutiveLoadFailures
+}
+
+// status returns the last time reminders were loaded successfully from the database, as well as the number of times
+// in a row that loading them has failed since then
+func (s *scheduler) status() (lastLoad time.Time, consecutiveLoadFailures int) {
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+	return s.lastLoad, s.consecutiveLoadFailures
+}
+
+// popDueReminders removes every reminder that is due at now from the queue and returns their notification message
+// IDs, as well as the time at which the next reminder is due (or zero if the queue is empty) and whether the queue
+// needs to be reloaded from the database, which is the case when reminders that weren't loaded may be due before the
+// next queued reminder
+func (s *scheduler) popDueReminders(now time.Time) (due []string, next time.Time, reloadNeeded bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for len(s.queue) > 0 {
+		first := s.queue[0]
+		if when, exists := s.scheduled[first.NotificationMessageID]; !exists || !when.Equal(first.Time) {
+			// The reminder was moved or removed since it was queued
+			heap.Pop(&s.queue)
+			continue
+		}
+		if first.Time.After(now) {
+			next = first.Time
+			break
+		}
+		heap.Pop(&s.queue)
+		delete(s.scheduled, first.NotificationMessageID)
+		delete(s.postponed, first.NotificationMessageID)
+		due = append(due, first.NotificationMessageID)
+	}
+	return due, next, !s.horizon.IsZero() && (len(s.queue) == 0 || next.After(s.horizon))
+}
+
+// deliverAll delivers reminders in parallel, but never more than maximumConcurrentDeliveries at the same time,
+// and returns once every reminder has been delivered
+func (s *scheduler) deliverAll(notificationMessageIDs []string) {
+	semaphore := make(chan struct{}, s.maximumConcurrentDeliveries)
+	waitGroup := sync.WaitGroup{}
+	for _, notificationMessageID := range notificationMessageIDs {
+		semaphore <- struct{}{}
+		waitGroup.Add(1)
+		go func(notificationMessageID string) {
+			defer func() {
+				<-semaphore
+				waitGroup.Done()
+			}()
+			s.deliver(notificationMessageID)
+		}(notificationMessageID)
+	}
+	waitGroup.Wait()
+}
+
+// run delivers reminders as they become due until ctx is cancelled. The batch of reminders being delivered when ctx is
+// cancelled, if any, is delivered entirely before returning.
+// afterEachBatch, if not nil, is called every time a batch of reminders has been delivered
+func (s *scheduler) run(ctx context.Context, afterEachBatch func()) {
+	var lastReload time.Time
+	reloadNeeded := true
+	for ctx.Err() == nil {
+		if reloadNeeded {
+			err := s.reload()
+			consecutiveLoadFailures := s.recordLoad(err)
+			if err != nil {
+				// The health check reports the scheduler as unhealthy if this keeps happening
+				slog.Error("[discord][scheduler] Failed to load reminders from database", "consecutive_failures", consecutiveLoadFailures, "error", err)
+				select {
+				case <-time.After(10 * time.Second):
+				case <-ctx.Done():
+				}
+				continue
+			}
+			lastReload, reloadNeeded = time.Now(), false
+		}
+		due, next, horizonReached := s.popDueReminders(time.Now())
+		if len(due) > 0 {
+			s.deliverAll(due)
+			if afterEachBatch != nil {
+				afterEachBatch()
+			}
+			continue
+		}
+		if horizonReached {
+			reloadNeeded = true
+			continue
+		}
+		wait := s.refreshInterval - time.Since(lastReload)
+		if !next.IsZero() && time.Until(next) < wait {
+			wait = time.Until(next)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+		}
+		if time.Since(lastReload) >= s.refreshInterval {
+			reloadNeeded = true
+		}
+	}
+}
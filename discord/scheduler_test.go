@@ -0,0 +1,214 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+)
+
+func TestScheduler_popDueReminders(t *testing.T) {
+	now := time.Now()
+	s := newScheduler(10, 1, time.Hour, nil, nil)
+	s.Schedule("1", now.Add(time.Hour))
+	s.Schedule("2", now.Add(-time.Minute))
+	s.Schedule("3", now.Add(-time.Hour))
+	s.Schedule("4", now.Add(-time.Second))
+	s.Unschedule("4")
+	s.Schedule("5", now.Add(-time.Minute))
+	s.Schedule("5", now.Add(30*time.Minute))
+	due, next, reloadNeeded := s.popDueReminders(now)
+	if len(due) != 2 || due[0] != "3" || due[1] != "2" {
+		t.Fatalf("expected reminders 3 and 2 to be due, got %v", due)
+	}
+	if !next.Equal(now.Add(30 * time.Minute)) {
+		t.Errorf("expected next reminder to be due at %s, got %s", now.Add(30*time.Minute), next)
+	}
+	if reloadNeeded {
+		t.Error("reload should not have been needed, because every reminder was loaded")
+	}
+	due, _, _ = s.popDueReminders(now)
+	if len(due) != 0 {
+		t.Errorf("expected no reminders to be due, got %v", due)
+	}
+}
+
+func TestScheduler_reloadWithHorizon(t *testing.T) {
+	now := time.Now()
+	s := newScheduler(2, 1, time.Hour, func(limit int) ([]*core.Reminder, error) {
+		return []*core.Reminder{
+			{NotificationMessageID: "1", Time: now.Add(-time.Minute)},
+			{NotificationMessageID: "2", Time: now.Add(time.Hour)},
+		}, nil
+	}, nil)
+	if err := s.reload(); err != nil {
+		t.Fatal("failed to reload:", err.Error())
+	}
+	// Reminders scheduled after the horizon should be ignored until the next reload
+	s.Schedule("3", now.Add(2*time.Hour))
+	s.Schedule("4", now.Add(30*time.Minute))
+	due, next, reloadNeeded := s.popDueReminders(now)
+	if len(due) != 1 || due[0] != "1" {
+		t.Fatalf("expected reminder 1 to be due, got %v", due)
+	}
+	if !next.Equal(now.Add(30 * time.Minute)) {
+		t.Errorf("expected next reminder to be due at %s, got %s", now.Add(30*time.Minute), next)
+	}
+	if reloadNeeded {
+		t.Error("reload should not have been needed, because the queue is not empty")
+	}
+	_, _, reloadNeeded = s.popDueReminders(now.Add(90 * time.Minute))
+	if !reloadNeeded {
+		t.Error("reload should've been needed, because the queue is empty and not every reminder was loaded")
+	}
+}
+
+func TestScheduler_PostponeSurvivesReload(t *testing.T) {
+	now := time.Now()
+	s := newScheduler(10, 1, time.Hour, func(limit int) ([]*core.Reminder, error) {
+		return []*core.Reminder{
+			{NotificationMessageID: "1", Time: now.Add(-time.Hour)},
+			{NotificationMessageID: "2", Time: now.Add(-time.Hour)},
+		}, nil
+	}, nil)
+	if err := s.reload(); err != nil {
+		t.Fatal("failed to reload:", err.Error())
+	}
+	s.popDueReminders(now)
+	s.Postpone("1", now.Add(10*time.Minute))
+	s.Postpone("2", now.Add(10*time.Minute))
+	// Scheduling a postponed reminder again means that it was moved, so it should no longer be postponed
+	s.Schedule("2", now.Add(-time.Hour))
+	if err := s.reload(); err != nil {
+		t.Fatal("failed to reload:", err.Error())
+	}
+	due, next, _ := s.popDueReminders(now)
+	if len(due) != 1 || due[0] != "2" {
+		t.Fatalf("expected only reminder 2 to be due, got %v", due)
+	}
+	if !next.Equal(now.Add(10 * time.Minute)) {
+		t.Errorf("expected reminder 1 to still be postponed until %s, got %s", now.Add(10*time.Minute), next)
+	}
+	if due, _, _ = s.popDueReminders(now.Add(10 * time.Minute)); len(due) != 1 || due[0] != "1" {
+		t.Fatalf("expected reminder 1 to be due once postponed, got %v", due)
+	}
+	if _, postponed := s.postponed["1"]; postponed {
+		t.Error("reminder 1 should no longer be postponed once due")
+	}
+}
+
+func TestScheduler_reloadWithFullQueueOfPostponedReminders(t *testing.T) {
+	now := time.Now()
+	var reminders []*core.Reminder
+	for i := 1; i <= 6; i++ {
+		reminders = append(reminders, &core.Reminder{NotificationMessageID: strconv.Itoa(i), Time: now.Add(time.Duration(i-10) * time.Minute)})
+	}
+	s := newScheduler(3, 1, time.Hour, func(limit int) ([]*core.Reminder, error) {
+		if limit > len(reminders) {
+			limit = len(reminders)
+		}
+		return reminders[:limit], nil
+	}, nil)
+	if err := s.reload(); err != nil {
+		t.Fatal("failed to reload:", err.Error())
+	}
+	// The delivery of every reminder in the queue fails, e.g. because Discord is unavailable
+	due, _, _ := s.popDueReminders(now)
+	if len(due) != 3 {
+		t.Fatalf("expected the 3 reminders loaded to be due, got %v", due)
+	}
+	for _, notificationMessageID := range due {
+		s.Postpone(notificationMessageID, now.Add(time.Hour))
+	}
+	if _, _, reloadNeeded := s.popDueReminders(now); !reloadNeeded {
+		t.Fatal("reload should've been needed, because reminders that weren't loaded are due before the postponed ones")
+	}
+	if err := s.reload(); err != nil {
+		t.Fatal("failed to reload:", err.Error())
+	}
+	due, _, _ = s.popDueReminders(now)
+	if len(due) != 3 || due[0] != "4" || due[1] != "5" || due[2] != "6" {
+		t.Fatalf("expected the reminders after the postponed ones to be due, got %v", due)
+	}
+	// Once delivered, reminders are deleted from the database, while new ones are created
+	reminders = append(reminders[:3], &core.Reminder{NotificationMessageID: "7", Time: now.Add(30 * time.Minute)})
+	if err := s.reload(); err != nil {
+		t.Fatal("failed to reload:", err.Error())
+	}
+	s.Schedule("8", now.Add(45*time.Minute))
+	due, next, reloadNeeded := s.popDueReminders(now)
+	if len(due) != 0 || reloadNeeded {
+		t.Fatalf("expected no reminder to be due and no reload to be needed, got %v and %v", due, reloadNeeded)
+	}
+	if !next.Equal(now.Add(30 * time.Minute)) {
+		t.Errorf("expected next reminder to be due at %s, got %s", now.Add(30*time.Minute), next)
+	}
+	if due, _, _ = s.popDueReminders(now.Add(45 * time.Minute)); len(due) != 2 || due[0] != "7" || due[1] != "8" {
+		t.Errorf("expected reminders scheduled while others were postponed to be due, got %v", due)
+	}
+}
+
+func TestScheduler_run(t *testing.T) {
+	delivered := make(chan string, 10)
+	s := newScheduler(10, 2, time.Hour, func(limit int) ([]*core.Reminder, error) {
+		return []*core.Reminder{{NotificationMessageID: "1", Time: time.Now().Add(-time.Hour)}}, nil
+	}, func(notificationMessageID string) {
+		delivered <- notificationMessageID
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		s.run(ctx, nil)
+		close(stopped)
+	}()
+	expectDelivery := func(expectedNotificationMessageID string) {
+		select {
+		case notificationMessageID := <-delivered:
+			if notificationMessageID != expectedNotificationMessageID {
+				t.Fatalf("expected reminder %s to be delivered, got %s", expectedNotificationMessageID, notificationMessageID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("reminder %s should've been delivered", expectedNotificationMessageID)
+		}
+	}
+	expectDelivery("1")
+	start := time.Now()
+	s.Schedule("2", time.Now().Add(50*time.Millisecond))
+	expectDelivery("2")
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("reminder was delivered too early (after %s)", elapsed)
+	}
+	s.Schedule("3", time.Now().Add(time.Hour))
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler should've stopped once its context was cancelled")
+	}
+}
+
+func TestScheduler_recordLoad(t *testing.T) {
+	s := newScheduler(10, 1, time.Hour, nil, nil)
+	if lastLoad, consecutiveLoadFailures := s.status(); !lastLoad.IsZero() || consecutiveLoadFailures != 0 {
+		t.Fatal("expected no load to have been recorded")
+	}
+	s.recordLoad(nil)
+	lastLoad, _ := s.status()
+	if lastLoad.IsZero() {
+		t.Fatal("expected last load to have been recorded")
+	}
+	s.recordLoad(errors.New("database is locked"))
+	if consecutiveLoadFailures := s.recordLoad(errors.New("database is locked")); consecutiveLoadFailures != 2 {
+		t.Errorf("expected 2 consecutive failures, got %d", consecutiveLoadFailures)
+	}
+	if lastFailedLoad, _ := s.status(); !lastFailedLoad.Equal(lastLoad) {
+		t.Error("failed loads should not change the last load")
+	}
+	s.recordLoad(nil)
+	if _, consecutiveLoadFailures := s.status(); consecutiveLoadFailures != 0 {
+		t.Errorf("expected consecutive failures to be reset, got %d", consecutiveLoadFailures)
+	}
+}
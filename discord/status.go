@@ -0,0 +1,63 @@
+package discord
+
+import (
+	"sync"
+	"time"
+)
+
+// Status is the state of the connection to Discord's gateway and of the worker delivering reminders
+type Status struct {
+	// StartedAt is when the bot started handling events and delivering reminders, or zero if it hasn't started yet
+	StartedAt time.Time
+
+	// GatewayConnected is whether the bot is currently connected to Discord's gateway
+	GatewayConnected bool
+
+	// GatewayStateChangedAt is when the bot last connected to or disconnected from Discord's gateway
+	GatewayStateChangedAt time.Time
+
+	// LastPollAt is when reminders were last loaded successfully from the database, or zero if they never were
+	LastPollAt time.Time
+
+	// ConsecutivePollFailures is the number of times in a row that reminders failed to be loaded from the database
+	ConsecutivePollFailures int
+}
+
+var (
+	statusMutex           sync.RWMutex
+	startedAt             time.Time
+	gatewayConnected      bool
+	gatewayStateChangedAt time.Time
+)
+
+// markStarted records that the bot, which is connected to the gateway, has started delivering reminders
+func markStarted() {
+	statusMutex.Lock()
+	defer statusMutex.Unlock()
+	startedAt = time.Now()
+	gatewayConnected, gatewayStateChangedAt = true, startedAt
+}
+
+func setGatewayConnected(connected bool) {
+	statusMutex.Lock()
+	defer statusMutex.Unlock()
+	if gatewayConnected != connected {
+		gatewayConnected, gatewayStateChangedAt = connected, time.Now()
+	}
+}
+
+// GetStatus returns the current state of the connection to Discord's gateway and of the worker delivering reminders
+func GetStatus() Status {
+	statusMutex.RLock()
+	status := Status{
+		StartedAt:             startedAt,
+		GatewayConnected:      gatewayConnected,
+		GatewayStateChangedAt: gatewayStateChangedAt,
+	}
+	statusMutex.RUnlock()
+	if !status.StartedAt.IsZero() {
+		// The scheduler is only guaranteed to be initialized once the bot has started
+		status.LastPollAt, status.ConsecutivePollFailures = reminderScheduler.status()
+	}
+	return status
+}
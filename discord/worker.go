@@ -1,41 +1,204 @@
 package discord
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"time"
 
+	"github.com/TwiN/discord-reminder-bot/core"
 	"github.com/TwiN/discord-reminder-bot/database"
+	"github.com/TwiN/discord-reminder-bot/logging"
+	"github.com/TwiN/discord-reminder-bot/metrics"
 	"github.com/bwmarrin/discordgo"
 )
 
-func worker(bot *discordgo.Session) {
-	for {
-		time.Sleep(10 * time.Second)
-		reminders, err := database.GetOverdueReminders()
+// worker delivers reminders as they become due until ctx is cancelled
+func worker(ctx context.Context, bot Client, store database.Store) {
+	reminderScheduler.run(ctx, func() {
+		updateListeningStatus(bot, store)
+	})
+}
+
+// deliverReminder sends a reminder that is due and persists the message sent before anything else
+func deliverReminder(bot Client, store database.Store, notificationMessageID string) {
+	ctx := logging.WithCorrelationID(context.Background())
+	reminder, err := store.GetReminderByNotificationMessageID(ctx, notificationMessageID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][deliverReminder] Failed to retrieve reminder from database", "error", err)
+		return
+	}
+	if reminder == nil {
+		// The reminder was deleted since it was scheduled
+		return
+	}
+	if reminder.Time.After(time.Now()) {
+		// The reminder was moved since it was scheduled
+		reminderScheduler.Schedule(reminder.NotificationMessageID, reminder.Time)
+		return
+	}
+	if len(reminder.DeliveredMessageID) > 0 {
+		slog.InfoContext(ctx, "[discord][deliverReminder] Reminder was already delivered, completing its delivery", "notification_message_id", reminder.NotificationMessageID)
+	} else {
+		deliveredMessage, err := sendReminder(ctx, bot, store, reminder)
 		if err != nil {
-			// TODO: if errors 5 times in a row, panic
-			log.Println("[discord][worker] Failed to retrieve expired reminders from database:", err.Error())
-			continue
+			handleFailedDelivery(ctx, bot, store, reminder, err)
+			return
+		}
+		metrics.RemindersDelivered.Inc()
+		metrics.DeliveryLag.Observe(time.Since(reminder.Time).Seconds())
+		reminder.DeliveryAttempts = 0
+		reminder.DeliveredMessageID = deliveredMessage.ID
+		if err = store.UpdateReminder(ctx, reminder); err != nil {
+			slog.ErrorContext(ctx, "[discord][deliverReminder] Failed to mark reminder as delivered", "notification_message_id", reminder.NotificationMessageID, "error", err)
+		}
+	}
+	if len(reminder.RoleID) == 0 {
+		// Keep track of the delivered reminder so that the user can snooze it
+		_ = store.CreateDeliveredReminder(ctx, &core.DeliveredReminder{
+			MessageID:   reminder.DeliveredMessageID,
+			UserID:      reminder.UserID,
+			ChannelID:   reminder.ChannelID,
+			MessageLink: reminder.MessageLink,
+			Note:        reminder.Note,
+			DeliveredAt: time.Now(),
+
+			MessageSnapshot: reminder.MessageSnapshot,
+		})
+	}
+	directMessageChannelID := getNotificationMessageChannelID(ctx, bot, reminder)
+	localizeReminder(ctx, store, reminder)
+	if reminder.ScheduleNextOccurrence(time.Now()) {
+		// The reminder is repeating, so instead of deleting it, we move it to its next occurrence
+		if err = updateReminder(ctx, store, reminder); err != nil {
+			// The row is left as is, so that the delivery is completed again the next time the reminder is loaded
+			slog.ErrorContext(ctx, "[discord][deliverReminder] Failed to move repeating reminder to its next occurrence", "notification_message_id", reminder.NotificationMessageID, "error", err)
+			return
 		}
-		if len(reminders) > 0 {
-			numberOfQueuedReminders, err := database.CountReminders()
-			if err == nil {
-				_ = bot.UpdateListeningStatus(fmt.Sprintf("%d queued reminders", numberOfQueuedReminders))
-			} else {
-				_ = bot.UpdateListeningStatus(botCommandPrefix + "RemindMe")
-			}
-		} else {
-			_ = bot.UpdateListeningStatus(botCommandPrefix + "RemindMe")
+		_, _ = updateExistingMessage(bot, directMessageChannelID, reminder.NotificationMessageID, "", reminder.GenerateNotificationMessageContent(getReminderLanguage(ctx, store, reminder.CreatorID, reminder)))
+		return
+	}
+	deleteReminder(ctx, bot, store, directMessageChannelID, reminder)
+}
+
+// sendReminder sends a reminder in its channel or by direct message, falling back on permanent errors
+func sendReminder(ctx context.Context, bot Client, store database.Store, reminder *core.Reminder) (*discordgo.Message, error) {
+	// Reminders for a role have no single recipient, so they are written in the language of the guild
+	language := getReminderLanguage(ctx, store, reminder.UserID, reminder)
+	// Reminders for a role can't be snoozed, because they are not delivered to a single user
+	var components []discordgo.MessageComponent
+	if len(reminder.RoleID) == 0 {
+		components = generateDeliveredReminderComponents(language)
+	}
+	if reminder.IsDeliveredInChannel() {
+		message, err := sendChannelMessage(bot, reminder.ChannelID, reminder.UserID, reminder.RoleID, "", reminder.GenerateReminderMessageContent(language), components)
+		if err == nil || !isPermanentDeliveryError(err) {
+			return message, err
 		}
-		for _, reminder := range reminders {
-			directMessage, err := sendDirectMessage(bot, reminder.UserID, "", reminder.GenerateReminderMessageContent())
-			if err != nil {
-				log.Printf("[discord][worker] Error: %s", err.Error())
-				_ = database.DeleteReminderByNotificationMessageID(reminder.NotificationMessageID)
-				continue
-			}
-			deleteReminder(bot, directMessage.ChannelID, reminder)
+		// The channel may have been deleted or the bot may no longer be allowed to post in it
+		slog.WarnContext(ctx, "[discord][sendReminder] Failed to deliver reminder in channel, falling back to direct message", "channel_id", reminder.ChannelID, "error", err)
+	}
+	// Reminders for a role are sent to their creator instead
+	recipientID := reminder.UserID
+	if len(reminder.RoleID) > 0 {
+		recipientID = reminder.CreatorID
+	}
+	message, err := sendDirectMessage(bot, recipientID, "", reminder.GenerateReminderMessageContent(language), components)
+	if err == nil || !isPermanentDeliveryError(err) || !deliveryFallbackToOriginChannel {
+		return message, err
+	}
+	originChannelID := getChannelIDFromLink(reminder.MessageLink)
+	if len(originChannelID) == 0 || originChannelID == reminder.ChannelID {
+		return nil, err
+	}
+	slog.WarnContext(ctx, "[discord][sendReminder] Failed to deliver reminder by direct message, falling back to the channel it was created from", "channel_id", originChannelID, "error", err)
+	message, fallbackErr := sendChannelMessage(bot, originChannelID, recipientID, "", "", language.Translate("I was unable to send you this reminder by direct message, so here it is.\n\n")+reminder.GenerateReminderMessageContent(language), components)
+	if fallbackErr != nil {
+		slog.WarnContext(ctx, "[discord][sendReminder] Failed to deliver reminder in the channel it was created from", "channel_id", originChannelID, "error", fallbackErr)
+		return nil, err
+	}
+	return message, nil
+}
+
+// handleFailedDelivery retries the delivery of a reminder later, or moves it to the failed reminders
+func handleFailedDelivery(ctx context.Context, bot Client, store database.Store, reminder *core.Reminder, err error) {
+	reminder.DeliveryAttempts++
+	isPermanent := isPermanentDeliveryError(err)
+	if isPermanent {
+		metrics.DeliveryAttemptsFailed.WithLabelValues("permanent").Inc()
+	} else {
+		metrics.DeliveryAttemptsFailed.WithLabelValues("transient").Inc()
+	}
+	if !isPermanent && reminder.DeliveryAttempts < limits.MaximumDeliveryAttempts {
+		retryDelay := limits.DeliveryRetryBaseDelay << (reminder.DeliveryAttempts - 1)
+		slog.WarnContext(ctx, "[discord][handleFailedDelivery] Failed to deliver reminder, retrying later", "notification_message_id", reminder.NotificationMessageID, "attempt", reminder.DeliveryAttempts, "maximum_attempts", limits.MaximumDeliveryAttempts, "retry_delay", retryDelay.String(), "error", err)
+		if updateErr := store.UpdateReminder(ctx, reminder); updateErr != nil {
+			slog.ErrorContext(ctx, "[discord][handleFailedDelivery] Failed to update number of delivery attempts", "error", updateErr)
 		}
+		reminderScheduler.Postpone(reminder.NotificationMessageID, time.Now().Add(retryDelay))
+		return
+	}
+	slog.ErrorContext(ctx, "[discord][handleFailedDelivery] Giving up on reminder", "notification_message_id", reminder.NotificationMessageID, "attempts", reminder.DeliveryAttempts, "error", err)
+	if moveErr := store.MoveReminderToFailedReminders(ctx, reminder, err.Error()); moveErr != nil {
+		// The reminder is left as is, so that its delivery is attempted again the next time reminders are reloaded
+		slog.ErrorContext(ctx, "[discord][handleFailedDelivery] Failed to move reminder to failed reminders", "error", moveErr)
+		return
+	}
+	reminderScheduler.Unschedule(reminder.NotificationMessageID)
+	metrics.RemindersFailed.Inc()
+	// Let the creator of the reminder know that it was not delivered through its notification message
+	localizeReminder(ctx, store, reminder)
+	language := getReminderLanguage(ctx, store, reminder.CreatorID, reminder)
+	embed := generateMessageEmbed("", "~~"+reminder.GenerateNotificationMessageContent(language)+"~~\n\n"+EmojiError+language.Translate(" This reminder could not be delivered."), embedColor)
+	_, _ = bot.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    getNotificationMessageChannelID(ctx, bot, reminder),
+		ID:         reminder.NotificationMessageID,
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &[]discordgo.MessageComponent{},
+	})
+}
+
+// isPermanentDeliveryError returns whether a delivery error will keep happening no matter how often it's retried
+func isPermanentDeliveryError(err error) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Response == nil {
+		return false
+	}
+	statusCode := restErr.Response.StatusCode
+	return statusCode >= 400 && statusCode < 500 && statusCode != http.StatusTooManyRequests && statusCode != http.StatusRequestTimeout
+}
+
+// getNotificationMessageChannelID returns the ID of the direct message channel of the reminder's creator
+func getNotificationMessageChannelID(ctx context.Context, bot Client, reminder *core.Reminder) string {
+	directMessageChannel, err := bot.UserChannelCreate(reminder.CreatorID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[discord][getNotificationMessageChannelID] Failed to open direct message", "user_id", reminder.CreatorID, "error", err)
+		return ""
+	}
+	return directMessageChannel.ID
+}
+
+// updateListeningStatus updates the bot's status with the number of queued reminders
+func updateListeningStatus(bot Client, store database.Store) {
+	ctx := logging.WithCorrelationID(context.Background())
+	numberOfQueuedReminders, err := store.CountReminders(ctx)
+	if err == nil {
+		metrics.QueuedReminders.Set(float64(numberOfQueuedReminders))
+	}
+	if err == nil && numberOfQueuedReminders > 0 {
+		_ = bot.UpdateListeningStatus(fmt.Sprintf("%d queued reminders", numberOfQueuedReminders))
+	} else {
+		_ = bot.UpdateListeningStatus(botCommandPrefix + "RemindMe")
+	}
+}
+
+// updateReminder persists the changes made to a reminder and notifies the scheduler of its new time
+func updateReminder(ctx context.Context, store database.Store, reminder *core.Reminder) error {
+	if err := store.UpdateReminder(ctx, reminder); err != nil {
+		return err
 	}
+	reminderScheduler.Schedule(reminder.NotificationMessageID, reminder.Time)
+	return nil
 }
@@ -0,0 +1,190 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/core"
+	"github.com/TwiN/discord-reminder-bot/database"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestIsPermanentDeliveryError(t *testing.T) {
+	restError := func(statusCode int) error {
+		return fmt.Errorf("failed to send DM to 1: %w", &discordgo.RESTError{Response: &http.Response{StatusCode: statusCode}})
+	}
+	scenarios := []struct {
+		Name     string
+		Err      error
+		Expected bool
+	}{
+		{Name: "forbidden", Err: restError(http.StatusForbidden), Expected: true},
+		{Name: "not-found", Err: restError(http.StatusNotFound), Expected: true},
+		{Name: "rate-limited", Err: restError(http.StatusTooManyRequests), Expected: false},
+		{Name: "server-error", Err: restError(http.StatusBadGateway), Expected: false},
+		{Name: "network-error", Err: errors.New("connection reset by peer"), Expected: false},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if isPermanentDeliveryError(scenario.Err) != scenario.Expected {
+				t.Errorf("expected %v, got %v", scenario.Expected, !scenario.Expected)
+			}
+		})
+	}
+}
+
+// createDueReminder creates a reminder through the bot as the user given, and then makes it due
+func createDueReminder(t *testing.T, bot *FakeClient, store *database.MemoryStore, user *discordgo.User, channelID string) *core.Reminder {
+	bot.InjectMessage("", bot.DirectMessageChannelID(user.ID), user, "!remindme 1h stretch")
+	reminders, _ := store.GetRemindersByCreatorID(context.Background(), user.ID, 0, 1)
+	if len(reminders) != 1 {
+		t.Fatal("reminder should've been created")
+	}
+	reminder := reminders[0]
+	reminder.ChannelID = channelID
+	reminder.Time = time.Now().Add(-time.Second)
+	// The channel is not part of the fields that can be updated, so the reminder is recreated instead
+	_ = store.DeleteReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID)
+	_ = store.CreateReminder(context.Background(), reminder)
+	return reminder
+}
+
+func TestDeliverReminder(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	reminder := createDueReminder(t, bot, store, user, "")
+	deliverReminder(bot, store, reminder.NotificationMessageID)
+	messages := bot.SentMessages(bot.DirectMessageChannelID(user.ID))
+	if len(messages) != 2 || !strings.Contains(messages[1].Embeds[0].Description, "asked me to remind you") || !strings.Contains(messages[1].Embeds[0].Description, "stretch") {
+		t.Fatal("reminder should've been delivered by direct message after its notification message, got", messages)
+	}
+	if deliveredReminder, _ := store.GetReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID); deliveredReminder != nil {
+		t.Error("reminder should've been deleted after being delivered")
+	}
+	if description := bot.Message(reminder.NotificationMessageID).Embeds[0].Description; !strings.HasPrefix(description, "~~") {
+		t.Error("notification message should've been crossed out, got", description)
+	}
+	if deliveredReminder, _ := store.GetDeliveredReminderByMessageID(context.Background(), messages[1].ID); deliveredReminder == nil {
+		t.Error("delivered reminder should've been kept so that it can be snoozed")
+	}
+}
+
+func TestDeliverReminder_CompletesDeliveryInterruptedByRestart(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	reminder := createDueReminder(t, bot, store, user, "")
+	// The bot stopped after sending the reminder, but before deleting it
+	reminder.DeliveredMessageID = "123"
+	_ = store.UpdateReminder(context.Background(), reminder)
+	deliverReminder(bot, store, reminder.NotificationMessageID)
+	if messages := bot.SentMessages(bot.DirectMessageChannelID(user.ID)); len(messages) != 1 {
+		t.Fatal("reminder should not have been sent again, got", messages)
+	}
+	if deliveredReminder, _ := store.GetReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID); deliveredReminder != nil {
+		t.Error("reminder should've been deleted")
+	}
+	if deliveredReminder, _ := store.GetDeliveredReminderByMessageID(context.Background(), "123"); deliveredReminder == nil {
+		t.Error("delivered reminder should've been kept so that it can be snoozed")
+	}
+}
+
+// storeFailingToUpdateReminders is a store that fails to update reminders
+type storeFailingToUpdateReminders struct {
+	*database.MemoryStore
+}
+
+func (store storeFailingToUpdateReminders) UpdateReminder(context.Context, *core.Reminder) error {
+	return errors.New("database is locked")
+}
+
+func TestDeliverReminder_KeepsRepeatingReminderThatFailedToBeMovedToItsNextOccurrence(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	reminder := createDueReminder(t, bot, store, user, "")
+	reminder.RepeatInterval = 24 * time.Hour
+	reminder.DeliveredMessageID = "123"
+	_ = store.UpdateReminder(context.Background(), reminder)
+	deliverReminder(bot, storeFailingToUpdateReminders{store}, reminder.NotificationMessageID)
+	keptReminder, _ := store.GetReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID)
+	if keptReminder == nil {
+		t.Fatal("repeating reminder should not have been deleted")
+	}
+	if !keptReminder.Time.Equal(reminder.Time) {
+		t.Error("repeating reminder should've been left as is, got time", keptReminder.Time)
+	}
+}
+
+func TestDeliverReminder_InChannel(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	bot.AddChannel("10", "11")
+	reminder := createDueReminder(t, bot, store, user, "11")
+	deliverReminder(bot, store, reminder.NotificationMessageID)
+	messages := bot.SentMessages("11")
+	if len(messages) != 1 || messages[0].Content != "<@2>" {
+		t.Fatal("reminder should've been delivered in the channel, mentioning the user, got", messages)
+	}
+}
+
+func TestDeliverReminder_FallsBackToDirectMessageWhenChannelIsUnavailable(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	bot.AddChannel("10", "11")
+	bot.Fail("ChannelMessageSendComplex", "11", NewFakeRESTError(http.StatusForbidden))
+	reminder := createDueReminder(t, bot, store, user, "11")
+	deliverReminder(bot, store, reminder.NotificationMessageID)
+	if messages := bot.SentMessages(bot.DirectMessageChannelID(user.ID)); len(messages) != 2 {
+		t.Fatal("reminder should've been delivered by direct message, got", messages)
+	}
+}
+
+func TestDeliverReminder_RetriesTransientFailuresAndGivesUpOnPermanentFailures(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	reminder := createDueReminder(t, bot, store, user, "")
+	directMessageChannelID := bot.DirectMessageChannelID(user.ID)
+	bot.Fail("ChannelMessageSendComplex", directMessageChannelID, NewFakeRESTError(http.StatusBadGateway))
+	deliverReminder(bot, store, reminder.NotificationMessageID)
+	retriedReminder, _ := store.GetReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID)
+	if retriedReminder == nil || retriedReminder.DeliveryAttempts != 1 {
+		t.Fatal("reminder should've been kept with 1 delivery attempt, got", retriedReminder)
+	}
+	bot.Fail("ChannelMessageSendComplex", directMessageChannelID, NewFakeRESTError(http.StatusForbidden))
+	deliverReminder(bot, store, reminder.NotificationMessageID)
+	if failedReminder, _ := store.GetReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID); failedReminder != nil {
+		t.Error("reminder should've been removed after failing to be delivered because of a permanent error")
+	}
+	failedReminder, _ := store.GetFailedReminderByNotificationMessageID(context.Background(), reminder.NotificationMessageID)
+	if failedReminder == nil || failedReminder.DeliveryAttempts != 2 {
+		t.Fatal("reminder should've been moved to failed reminders after 2 delivery attempts, got", failedReminder)
+	}
+	if description := bot.Message(reminder.NotificationMessageID).Embeds[0].Description; !strings.Contains(description, "could not be delivered") {
+		t.Error("notification message should've been updated to say that the reminder could not be delivered, got", description)
+	}
+}
+
+func TestDeliverReminder_QuotesMessageSnapshot(t *testing.T) {
+	bot, store := newFakeBot(t)
+	user := &discordgo.User{ID: "2", Username: "user"}
+	bot.AddChannel("10", "11")
+	message := bot.InjectMessage("10", "11", &discordgo.User{ID: "3", Username: "teammate"}, "can someone review my PR?")
+	bot.InjectReactionAdd("10", "11", message.ID, user.ID, reminderEmojis[0])
+	reminders, _ := store.GetRemindersByCreatorID(context.Background(), user.ID, 0, 1)
+	if len(reminders) != 1 {
+		t.Fatal("reminder should've been created")
+	}
+	// The message is edited after the reminder was created, but the reminder should quote the message as it was
+	message.Content = "nevermind"
+	reminders[0].Time = time.Now().Add(-time.Second)
+	_ = store.UpdateReminder(context.Background(), reminders[0])
+	deliverReminder(bot, store, reminders[0].NotificationMessageID)
+	messages := bot.SentMessages(bot.DirectMessageChannelID(user.ID))
+	if len(messages) != 2 || !strings.Contains(messages[1].Embeds[0].Description, "> can someone review my PR?\n> — **teammate**") {
+		t.Fatal("delivered reminder should've quoted the message as it was when the reminder was created, got", messages)
+	}
+}
@@ -4,48 +4,75 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+
+	"github.com/TwiN/discord-reminder-bot/i18n"
 )
 
+// PrettyDuration returns a human-readable representation of a duration in English, e.g. "2 days, 3 hours and 1 minute".
+// Durations of 30 days or more are expressed in months of 30 days and years of 365 days.
 func PrettyDuration(duration time.Duration) string {
+	return PrettyDurationIn(i18n.English, duration)
+}
+
+// PrettyDurationIn returns a human-readable representation of a duration in a language
+func PrettyDurationIn(language i18n.Language, duration time.Duration) string {
+	days := int(duration.Hours() / 24)
+	years := days / 365
+	months := days % 365 / 30
+	return joinParts(language, append(pluralizeMonths(language, years*12+months), pluralizeDuration(language, duration-time.Duration(years*365+months*30)*24*time.Hour)...))
+}
+
+// PrettyPeriod returns a human-readable representation of a period in English, e.g. "1 year and 2 months"
+func PrettyPeriod(period Period) string {
+	return PrettyPeriodIn(i18n.English, period)
+}
+
+// PrettyPeriodIn returns a human-readable representation of a period in a language
+func PrettyPeriodIn(language i18n.Language, period Period) string {
+	return joinParts(language, append(pluralizeMonths(language, period.Months), pluralizeDuration(language, period.Duration)...))
+}
+
+func pluralizeMonths(language i18n.Language, months int) []string {
+	var parts []string
+	if years := months / 12; years != 0 {
+		parts = append(parts, language.Plural(years, "%d year", "%d years"))
+	}
+	if months%12 != 0 {
+		parts = append(parts, language.Plural(months%12, "%d month", "%d months"))
+	}
+	return parts
+}
+
+func pluralizeDuration(language i18n.Language, duration time.Duration) []string {
 	days := int(duration.Hours() / 24)
 	hours := int(duration.Hours()) % 24
 	minutes := int(duration.Minutes()) % 60
 	seconds := int(duration.Seconds()) % 60
 	var parts []string
 	if days != 0 {
-		if days > 1 {
-			parts = append(parts, strconv.Itoa(days)+" days")
-		} else {
-			parts = append(parts, strconv.Itoa(days)+" day")
-		}
+		parts = append(parts, language.Plural(days, "%d day", "%d days"))
 	}
 	if hours != 0 {
-		if hours > 1 {
-			parts = append(parts, strconv.Itoa(hours)+" hours")
-		} else {
-			parts = append(parts, strconv.Itoa(hours)+" hour")
-		}
+		parts = append(parts, language.Plural(hours, "%d hour", "%d hours"))
 	}
 	if minutes != 0 {
-		if minutes > 1 {
-			parts = append(parts, strconv.Itoa(minutes)+" minutes")
-		} else {
-			parts = append(parts, strconv.Itoa(minutes)+" minute")
-		}
+		parts = append(parts, language.Plural(minutes, "%d minute", "%d minutes"))
 	}
 	if seconds != 0 {
-		if seconds > 1 {
-			parts = append(parts, strconv.Itoa(seconds)+" seconds")
-		} else {
-			parts = append(parts, strconv.Itoa(seconds)+" second")
-		}
+		parts = append(parts, language.Plural(seconds, "%d second", "%d seconds"))
 	}
+	return parts
+}
+
+// joinParts joins parts with commas, except for the last two parts which are joined with "and"
+func joinParts(language i18n.Language, parts []string) string {
 	var pretty string // 1h 2m 3s
 	for i := range parts {
 		if i == 0 {
 			pretty = parts[i]
 		} else if i+1 == len(parts) {
-			pretty = pretty + " and " + parts[i]
+			pretty = pretty + " " + language.Translate("and") + " " + parts[i]
 		} else {
 			pretty = pretty + ", " + parts[i]
 		}
@@ -53,65 +80,252 @@ func PrettyDuration(duration time.Duration) string {
 	return pretty
 }
 
-// ParseDuration is a more lenient version of time.ParseDuration
+// ParseDuration parses a duration expressed in natural language, such as "2h30m", "90 seconds", "1 day 3 hours",
+// "1.5h", "half an hour" or "two weeks". Unlike ParsePeriodPrefix, the entirety of s must be a duration, and since
+// months and years do not have a fixed length, they are not supported.
 func ParseDuration(s string) (time.Duration, error) {
-	s = strings.ToLower(s)
-	if s == "tomorrow" {
-		s = "24h"
-	}
-	var carryOver time.Duration
-	duration, err := time.ParseDuration(s)
+	period, _, rest, err := ParsePeriodPrefix(s)
 	if err != nil {
-		if strings.Contains(s, "min") {
-			s = strings.ReplaceAll(s, "minutes", "m")
-			s = strings.ReplaceAll(s, "minute", "m")
-			s = strings.ReplaceAll(s, "mins", "m")
-			s = strings.ReplaceAll(s, "min", "m")
-		}
-		if strings.Contains(s, "hour") {
-			s = strings.ReplaceAll(s, "hours", "h")
-			s = strings.ReplaceAll(s, "hour", "h")
-		}
-		if strings.Contains(s, "hr") {
-			s = strings.ReplaceAll(s, "hrs", "h")
-			s = strings.ReplaceAll(s, "hr", "h")
-		}
-		if strings.Contains(s, "day") {
-			s = strings.ReplaceAll(s, "days", "d")
-			s = strings.ReplaceAll(s, "day", "d")
-		}
-		if strings.Contains(s, "d") {
-			numberStart, numberEnd := -1, -1
-			for i := range s {
-				if s[i] >= '0' && s[i] <= '9' {
-					if numberStart == -1 {
-						numberStart = i
-					}
-				} else {
-					if s[i] == 'd' {
-						numberEnd = i
-						break
-					} else {
-						numberStart = -1
-					}
-				}
-			}
-			if numberStart > -1 && numberEnd >= numberStart {
-				numberOfDays, _ := strconv.Atoi(s[numberStart:numberEnd])
-				carryOver = time.Duration(numberOfDays) * 24 * time.Hour
-				s = s[:numberStart] + s[numberEnd+1:]
-				// If the only duration in s was a number of days, we'll return the carryover right now, otherwise
-				// time.ParseDuration(s) will fail because s == ""
-				if len(s) == 0 {
-					return carryOver, nil
+		return 0, err
+	}
+	if len(strings.TrimSpace(rest)) > 0 {
+		return 0, ErrInvalidDuration
+	}
+	if period.Months != 0 {
+		return 0, ErrCalendarDuration
+	}
+	return period.Duration, nil
+}
+
+// ParsePeriodPrefix parses the duration at the beginning of s and returns it along with the number of
+// whitespace-separated tokens it spanned and everything in s that comes after it.
+//
+// A duration is made of one or more components, each of which is either a compact duration (e.g. "2d1h", "1.5h",
+// "5mins", "1y6mo") or a number followed by a unit (e.g. "2 hours", "two days", "a week", "half an hour",
+// "1 and a half days", "3 months"). Units, spelled-out numbers and "and" may also be written in French or German
+// (e.g. "2 jours", "drei Stunden und 10 Minuten"). Months and years are kept apart from the rest of the duration in the returned
+// Period, because their length depends on the time they are added to.
+// Components may be separated by "and" or by commas, and each component must use a smaller unit than the one before
+// it, so that in "1h 2 days overdue", only "1h" is parsed as the duration.
+func ParsePeriodPrefix(s string) (Period, int, string, error) {
+	tokens := tokenize(s)
+	if len(tokens) > 0 && tokens[0].text == "tomorrow" {
+		// For backward compatibility, "tomorrow" on its own means in 24 hours
+		return Period{Duration: 24 * time.Hour}, 1, s[tokens[0].end:], nil
+	}
+	var total Period
+	var consumedTokens int
+	var previousUnit time.Duration
+	for consumedTokens < len(tokens) {
+		start := consumedTokens
+		if consumedTokens > 0 && durationConjunctions[tokens[start].text] {
+			start++
+		}
+		period, unit, numberOfTokens, err := parseDurationComponent(tokens[start:])
+		if err != nil {
+			return Period{}, 0, s, err
+		}
+		if numberOfTokens == 0 || (previousUnit != 0 && unit >= previousUnit) {
+			break
+		}
+		if total, err = total.plus(period); err != nil {
+			return Period{}, 0, s, err
+		}
+		previousUnit = unit
+		consumedTokens = start + numberOfTokens
+	}
+	if consumedTokens == 0 {
+		return Period{}, 0, s, ErrInvalidDuration
+	}
+	return total, consumedTokens, s[tokens[consumedTokens-1].end:], nil
+}
+
+// ErrInvalidDuration is returned when a duration cannot be parsed
+var ErrInvalidDuration = i18n.Errorf("invalid duration, expected something like 2h30m, 90 seconds or 1 day 3 hours")
+
+// ErrCalendarDuration is returned by ParseDuration when a duration is expressed in months or years
+var ErrCalendarDuration = i18n.Errorf("months and years do not have a fixed duration")
+
+// ErrDurationTooLong is returned when a duration is too long to be represented
+var ErrDurationTooLong = i18n.Errorf("duration is too long")
+
+const (
+	// month and year are the units of months and years in durationUnits. Their values are only used to compare them to
+	// other units, because months and years are added to times using the calendar.
+	month = 30 * 24 * time.Hour
+	year  = 365 * 24 * time.Hour
+)
+
+// durationUnits are the units of durations in every supported language
+var durationUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "wks": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+	"mo": month, "mos": month, "month": month, "months": month,
+	"y": year, "yr": year, "yrs": year, "year": year, "years": year,
+	// French
+	"seconde": time.Second, "secondes": time.Second, "heure": time.Hour, "heures": time.Hour,
+	"jour": 24 * time.Hour, "jours": 24 * time.Hour, "j": 24 * time.Hour,
+	"semaine": 7 * 24 * time.Hour, "semaines": 7 * 24 * time.Hour, "sem": 7 * 24 * time.Hour,
+	"mois": month, "an": year, "ans": year, "année": year, "années": year,
+	// German
+	"sekunde": time.Second, "sekunden": time.Second, "minuten": time.Minute,
+	"stunde": time.Hour, "stunden": time.Hour, "std": time.Hour,
+	"tag": 24 * time.Hour, "tage": 24 * time.Hour, "tagen": 24 * time.Hour,
+	"woche": 7 * 24 * time.Hour, "wochen": 7 * 24 * time.Hour,
+	"monat": month, "monate": month, "monaten": month, "jahr": year, "jahre": year, "jahren": year,
+}
+
+// spelledOutNumbers are the numbers that may be spelled out in durations, in every supported language
+var spelledOutNumbers = map[string]float64{
+	"a": 1, "an": 1, "zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8,
+	"nine": 9, "ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16,
+	"seventeen": 17, "eighteen": 18, "nineteen": 19, "twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+	"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+	// French
+	"un": 1, "une": 1, "deux": 2, "trois": 3, "quatre": 4, "cinq": 5, "sept": 7, "huit": 8, "neuf": 9, "dix": 10,
+	"onze": 11, "douze": 12, "quinze": 15, "vingt": 20, "trente": 30, "quarante": 40, "cinquante": 50,
+	// German
+	"ein": 1, "eine": 1, "einen": 1, "zwei": 2, "drei": 3, "vier": 4, "fünf": 5, "sechs": 6, "sieben": 7, "acht": 8,
+	"neun": 9, "zehn": 10, "elf": 11, "zwölf": 12, "fünfzehn": 15, "zwanzig": 20, "dreißig": 30, "vierzig": 40,
+	"fünfzig": 50,
+}
+
+// durationConjunctions are the words that may separate the components of a duration, in every supported language
+var durationConjunctions = map[string]bool{"and": true, "et": true, "und": true}
+
+// durationToken is a lowercase whitespace-separated token of the string passed to ParseDurationPrefix
+type durationToken struct {
+	text string // Token, without trailing commas
+	end  int    // Index right after the token in the original string
+}
+
+func tokenize(s string) []durationToken {
+	var tokens []durationToken
+	offset := 0
+	for {
+		token, rest := nextToken(s[offset:])
+		if len(token) == 0 {
+			return tokens
+		}
+		offset = len(s) - len(rest)
+		tokens = append(tokens, durationToken{text: strings.ToLower(strings.TrimRight(token, ",")), end: offset})
+	}
+}
+
+// parseDurationComponent parses a single component of a duration at the beginning of tokens and returns its value,
+// its unit and the number of tokens it spanned. If tokens doesn't start with a duration component, 0 tokens are
+// returned. If the component is too long to be represented, ErrDurationTooLong is returned.
+func parseDurationComponent(tokens []durationToken) (Period, time.Duration, int, error) {
+	if len(tokens) == 0 {
+		return Period{}, 0, 0, nil
+	}
+	// e.g. "half an hour", "half a day" or "half hour"
+	if tokens[0].text == "half" {
+		numberOfTokens := 1
+		if len(tokens) > 1 && (tokens[1].text == "a" || tokens[1].text == "an") {
+			numberOfTokens++
+		}
+		if len(tokens) > numberOfTokens {
+			if unit, ok := durationUnits[tokens[numberOfTokens].text]; ok {
+				if period, ok, err := newPeriod(0.5, unit); ok {
+					return period, unit, numberOfTokens + 1, err
 				}
 			}
 		}
+		return Period{}, 0, 0, nil
+	}
+	// e.g. "2d1h" or "1.5h"
+	if period, unit, ok, err := parseCompactDuration(tokens[0].text); ok {
+		return period, unit, 1, err
+	}
+	// e.g. "2 hours", "two days" or "1 and a half days"
+	number, ok := parseNumber(tokens[0].text)
+	if !ok {
+		return Period{}, 0, 0, nil
+	}
+	numberOfTokens := 1
+	if len(tokens) > 4 && tokens[1].text == "and" && tokens[2].text == "a" && tokens[3].text == "half" {
+		if _, ok := durationUnits[tokens[4].text]; ok {
+			number += 0.5
+			numberOfTokens += 3
+		}
+	}
+	if len(tokens) <= numberOfTokens {
+		return Period{}, 0, 0, nil
+	}
+	unit, ok := durationUnits[tokens[numberOfTokens].text]
+	if !ok {
+		return Period{}, 0, 0, nil
+	}
+	numberOfTokens++
+	// e.g. "an hour and a half"
+	if len(tokens) > numberOfTokens+2 && tokens[numberOfTokens].text == "and" && tokens[numberOfTokens+1].text == "a" && tokens[numberOfTokens+2].text == "half" {
+		number += 0.5
+		numberOfTokens += 3
+	}
+	period, ok, err := newPeriod(number, unit)
+	if !ok {
+		return Period{}, 0, 0, nil
+	}
+	return period, unit, numberOfTokens, err
+}
+
+// parseCompactDuration parses a duration made of one or more numbers immediately followed by their unit, like "2d1h",
+// "1.5h" or "5mins", and returns it along with its smallest unit. If s is a duration that is too long to be represented,
+// ErrDurationTooLong is returned.
+func parseCompactDuration(s string) (Period, time.Duration, bool, error) {
+	var total Period
+	var previousUnit time.Duration
+	for len(s) > 0 {
+		numberEnd := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if numberEnd <= 0 {
+			return Period{}, 0, false, nil
+		}
+		unitEnd := strings.IndexFunc(s[numberEnd:], func(r rune) bool { return !unicode.IsLetter(r) })
+		if unitEnd == -1 {
+			unitEnd = len(s) - numberEnd
+		}
+		number, ok := parseNumber(s[:numberEnd])
+		unit, isUnit := durationUnits[s[numberEnd:numberEnd+unitEnd]]
+		if !ok || !isUnit || (previousUnit != 0 && unit >= previousUnit) {
+			return Period{}, 0, false, nil
+		}
+		period, ok, err := newPeriod(number, unit)
+		if !ok {
+			return Period{}, 0, false, nil
+		}
+		if err == nil {
+			total, err = total.plus(period)
+		}
+		if err != nil {
+			return Period{}, 0, true, err
+		}
+		previousUnit = unit
+		s = s[numberEnd+unitEnd:]
+	}
+	return total, previousUnit, previousUnit != 0, nil
+}
+
+// parseNumber parses a non-negative number, which may be fractional (e.g. "1.5") or spelled out (e.g. "two",
+// "twenty-five")
+func parseNumber(s string) (float64, bool) {
+	if number, ok := spelledOutNumbers[s]; ok {
+		return number, true
+	}
+	if tens, units, found := strings.Cut(s, "-"); found {
+		tensNumber, isTens := spelledOutNumbers[tens]
+		unitsNumber, isUnits := spelledOutNumbers[units]
+		if isTens && isUnits && tensNumber >= 20 && int(tensNumber)%10 == 0 && unitsNumber >= 1 && unitsNumber <= 9 && units != "a" && units != "an" {
+			return tensNumber + unitsNumber, true
+		}
+		return 0, false
 	}
-	// Now that s has been sanitized, we can try parsing it again
-	duration, err = time.ParseDuration(s)
-	if err == nil {
-		duration += carryOver
+	if len(s) == 0 || strings.Trim(s, "0123456789.") != "" {
+		return 0, false
 	}
-	return duration, err
+	number, err := strconv.ParseFloat(s, 64)
+	return number, err == nil
 }
@@ -3,6 +3,8 @@ package format
 import (
 	"testing"
 	"time"
+
+	"github.com/TwiN/discord-reminder-bot/i18n"
 )
 
 func TestPrettyDuration(t *testing.T) {
@@ -42,6 +44,26 @@ func TestPrettyDuration(t *testing.T) {
 			Input:          26*time.Hour + 3*time.Minute + 4*time.Second,
 			ExpectedOutput: "1 day, 2 hours, 3 minutes and 4 seconds",
 		},
+		{
+			Name:           "29d",
+			Input:          29 * 24 * time.Hour,
+			ExpectedOutput: "29 days",
+		},
+		{
+			Name:           "45d",
+			Input:          45 * 24 * time.Hour,
+			ExpectedOutput: "1 month and 15 days",
+		},
+		{
+			Name:           "730d",
+			Input:          730 * 24 * time.Hour,
+			ExpectedOutput: "2 years",
+		},
+		{
+			Name:           "400d2h",
+			Input:          400*24*time.Hour + 2*time.Hour,
+			ExpectedOutput: "1 year, 1 month, 5 days and 2 hours",
+		},
 	}
 	for _, scenario := range scenarios {
 		t.Run(scenario.Name, func(t *testing.T) {
@@ -53,6 +75,48 @@ func TestPrettyDuration(t *testing.T) {
 	}
 }
 
+func TestPrettyDurationIn(t *testing.T) {
+	type Scenario struct {
+		Name           string
+		Language       i18n.Language
+		Input          time.Duration
+		ExpectedOutput string
+	}
+	scenarios := []Scenario{
+		{
+			Name:           "fr-1d2h30m",
+			Language:       i18n.French,
+			Input:          26*time.Hour + 30*time.Minute,
+			ExpectedOutput: "1 jour, 2 heures et 30 minutes",
+		},
+		{
+			Name:           "fr-1m1s",
+			Language:       i18n.French,
+			Input:          time.Minute + time.Second,
+			ExpectedOutput: "1 minute et 1 seconde",
+		},
+		{
+			Name:           "de-1h1m",
+			Language:       i18n.German,
+			Input:          time.Hour + time.Minute,
+			ExpectedOutput: "1 Stunde und 1 Minute",
+		},
+		{
+			Name:           "de-400d",
+			Language:       i18n.German,
+			Input:          400 * 24 * time.Hour,
+			ExpectedOutput: "1 Jahr, 1 Monat und 5 Tage",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if output := PrettyDurationIn(scenario.Language, scenario.Input); output != scenario.ExpectedOutput {
+				t.Errorf("expected '%s', got '%s'", scenario.ExpectedOutput, output)
+			}
+		})
+	}
+}
+
 func TestParseDuration(t *testing.T) {
 	type Scenario struct {
 		Input          string
@@ -125,6 +189,171 @@ func TestParseDuration(t *testing.T) {
 			ExpectedOutput: 99*time.Hour + 2*time.Minute + 1*time.Second,
 			ExpectedErr:    nil,
 		},
+		{
+			Input:          "1w",
+			ExpectedOutput: 7 * 24 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "2 hours",
+			ExpectedOutput: 2 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "1 day 3 hours",
+			ExpectedOutput: 27 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "1 day, 3 hours and 30 minutes",
+			ExpectedOutput: 27*time.Hour + 30*time.Minute,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "1.5h",
+			ExpectedOutput: 90 * time.Minute,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "1.5 days",
+			ExpectedOutput: 36 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "half an hour",
+			ExpectedOutput: 30 * time.Minute,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "an hour and a half",
+			ExpectedOutput: 90 * time.Minute,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "1 and a half days",
+			ExpectedOutput: 36 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "a week",
+			ExpectedOutput: 7 * 24 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "90 seconds",
+			ExpectedOutput: 90 * time.Second,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "two days",
+			ExpectedOutput: 48 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "Twenty-five Minutes",
+			ExpectedOutput: 25 * time.Minute,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "2 weeks 1 day",
+			ExpectedOutput: 15 * 24 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "tomorrow",
+			ExpectedOutput: 24 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "3 months",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrCalendarDuration,
+		},
+		{
+			Input:          "1y",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrCalendarDuration,
+		},
+		{
+			Input:          "",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrInvalidDuration,
+		},
+		{
+			Input:          "hours",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrInvalidDuration,
+		},
+		{
+			Input:          "two",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrInvalidDuration,
+		},
+		{
+			Input:          "100000000000h",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrDurationTooLong,
+		},
+		{
+			Input:          "100000000000 hours",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrDurationTooLong,
+		},
+		{
+			Input:          "2562047h 47 minutes 17 seconds",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrDurationTooLong,
+		},
+		{
+			Input:          "2562047h47m16s",
+			ExpectedOutput: 2562047*time.Hour + 47*time.Minute + 16*time.Second,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "1h1d",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrInvalidDuration,
+		},
+		{
+			Input:          "1 hour 2 days",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrInvalidDuration,
+		},
+		{
+			Input:          "5 apples",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrInvalidDuration,
+		},
+		{
+			Input:          "-5m",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrInvalidDuration,
+		},
+		{
+			Input:          "2 jours",
+			ExpectedOutput: 48 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "3 Stunden",
+			ExpectedOutput: 3 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "drei Stunden und 10 Minuten",
+			ExpectedOutput: 3*time.Hour + 10*time.Minute,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "1j12h",
+			ExpectedOutput: 36 * time.Hour,
+			ExpectedErr:    nil,
+		},
+		{
+			Input:          "2 mois",
+			ExpectedOutput: 0,
+			ExpectedErr:    ErrCalendarDuration,
+		},
 	}
 	for _, scenario := range scenarios {
 		t.Run(scenario.Input, func(t *testing.T) {
@@ -138,3 +367,91 @@ func TestParseDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePeriodPrefix(t *testing.T) {
+	type Scenario struct {
+		Input                  string
+		ExpectedOutput         Period
+		ExpectedConsumedTokens int
+		ExpectedRest           string
+	}
+	scenarios := []Scenario{
+		{
+			Input:                  "2h30m note",
+			ExpectedOutput:         Period{Duration: 2*time.Hour + 30*time.Minute},
+			ExpectedConsumedTokens: 1,
+			ExpectedRest:           " note",
+		},
+		{
+			Input:                  "2 hours take out the trash",
+			ExpectedOutput:         Period{Duration: 2 * time.Hour},
+			ExpectedConsumedTokens: 2,
+			ExpectedRest:           " take out the trash",
+		},
+		{
+			Input:                  "1 day and 3 hours, then call mom",
+			ExpectedOutput:         Period{Duration: 27 * time.Hour},
+			ExpectedConsumedTokens: 5,
+			ExpectedRest:           " then call mom",
+		},
+		{
+			Input:                  "half an hour and then some",
+			ExpectedOutput:         Period{Duration: 30 * time.Minute},
+			ExpectedConsumedTokens: 3,
+			ExpectedRest:           " and then some",
+		},
+		{
+			Input:                  "1h 2 days overdue",
+			ExpectedOutput:         Period{Duration: time.Hour},
+			ExpectedConsumedTokens: 1,
+			ExpectedRest:           " 2 days overdue",
+		},
+		{
+			Input:                  "5m 2 people to call",
+			ExpectedOutput:         Period{Duration: 5 * time.Minute},
+			ExpectedConsumedTokens: 1,
+			ExpectedRest:           " 2 people to call",
+		},
+		{
+			Input:                  "1 year and 6 months, renew the contract",
+			ExpectedOutput:         Period{Months: 18},
+			ExpectedConsumedTokens: 5,
+			ExpectedRest:           " renew the contract",
+		},
+		{
+			Input:                  "1y2mo3d rotate certificates",
+			ExpectedOutput:         Period{Months: 14, Duration: 72 * time.Hour},
+			ExpectedConsumedTokens: 1,
+			ExpectedRest:           " rotate certificates",
+		},
+		{
+			Input:                  "half a year",
+			ExpectedOutput:         Period{Months: 6},
+			ExpectedConsumedTokens: 3,
+			ExpectedRest:           "",
+		},
+		{
+			Input:                  "2 days half a month",
+			ExpectedOutput:         Period{Duration: 48 * time.Hour},
+			ExpectedConsumedTokens: 2,
+			ExpectedRest:           " half a month",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Input, func(t *testing.T) {
+			output, consumedTokens, rest, err := ParsePeriodPrefix(scenario.Input)
+			if err != nil {
+				t.Fatalf("[%s] expected no error, got %v", scenario.Input, err)
+			}
+			if output != scenario.ExpectedOutput {
+				t.Errorf("[%s] expected output to be %v, got %v", scenario.Input, scenario.ExpectedOutput, output)
+			}
+			if consumedTokens != scenario.ExpectedConsumedTokens {
+				t.Errorf("[%s] expected %d tokens to be consumed, got %d", scenario.Input, scenario.ExpectedConsumedTokens, consumedTokens)
+			}
+			if rest != scenario.ExpectedRest {
+				t.Errorf("[%s] expected rest to be %q, got %q", scenario.Input, scenario.ExpectedRest, rest)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package format
+
+import (
+	"math"
+	"time"
+)
+
+// Period is a duration that may include calendar months, whose length depends on the time they are added to
+type Period struct {
+	Months   int           // Number of calendar months, where a year is 12 months
+	Duration time.Duration // Fixed duration added after the months
+}
+
+// IsZero returns whether the period is empty
+func (p Period) IsZero() bool {
+	return p.Months == 0 && p.Duration == 0
+}
+
+// AddTo returns t plus the period, adding the months using the calendar of the location of t
+func (p Period) AddTo(t time.Time) time.Time {
+	return AddMonths(t, p.Months).Add(p.Duration)
+}
+
+// plus returns the sum of two non-negative periods, or ErrDurationTooLong if it can't be represented
+func (p Period) plus(other Period) (Period, error) {
+	if p.Duration > math.MaxInt64-other.Duration || p.Months > math.MaxInt32-other.Months {
+		return Period{}, ErrDurationTooLong
+	}
+	return Period{Months: p.Months + other.Months, Duration: p.Duration + other.Duration}, nil
+}
+
+// AddMonths adds a number of calendar months to t.
+// Unlike time.Time.AddDate, days that don't exist in the resulting month are clamped to the last day of that month
+// instead of overflowing into the next one, so January 31st plus one month is the last day of February.
+func AddMonths(t time.Time, months int) time.Time {
+	result := t.AddDate(0, months, 0)
+	if result.Day() != t.Day() {
+		// The day overflowed into the next month, so we go back to the last day of the month before
+		result = result.AddDate(0, 0, -result.Day())
+	}
+	return result
+}
+
+// newPeriod creates a Period from a number of units. Returns false if the number of months is not a whole number, and
+// ErrDurationTooLong if the period can't be represented.
+func newPeriod(number float64, unit time.Duration) (Period, bool, error) {
+	switch unit {
+	case month, year:
+		months := number
+		if unit == year {
+			months *= 12
+		}
+		if months != math.Trunc(months) {
+			return Period{}, false, nil
+		}
+		if months > math.MaxInt32 {
+			return Period{}, true, ErrDurationTooLong
+		}
+		return Period{Months: int(months)}, true, nil
+	}
+	// float64(math.MaxInt64) is 2^63, which is already out of range, and converting a float that is out of range to an
+	// integer gives a result that depends on the platform
+	duration := math.Round(number * float64(unit))
+	if duration >= math.MaxInt64 {
+		return Period{}, true, ErrDurationTooLong
+	}
+	return Period{Duration: time.Duration(duration)}, true, nil
+}
@@ -0,0 +1,103 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddMonths(t *testing.T) {
+	type Scenario struct {
+		Name           string
+		Input          time.Time
+		Months         int
+		ExpectedOutput time.Time
+	}
+	scenarios := []Scenario{
+		{
+			Name:           "same-day-next-month",
+			Input:          time.Date(2026, 11, 4, 14, 0, 0, 0, time.UTC),
+			Months:         1,
+			ExpectedOutput: time.Date(2026, 12, 4, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:           "end-of-january-to-end-of-february",
+			Input:          time.Date(2027, 1, 31, 9, 0, 0, 0, time.UTC),
+			Months:         1,
+			ExpectedOutput: time.Date(2027, 2, 28, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:           "end-of-january-to-end-of-february-in-leap-year",
+			Input:          time.Date(2028, 1, 31, 9, 0, 0, 0, time.UTC),
+			Months:         1,
+			ExpectedOutput: time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:           "leap-day-to-next-year",
+			Input:          time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC),
+			Months:         12,
+			ExpectedOutput: time.Date(2029, 2, 28, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:           "across-years",
+			Input:          time.Date(2026, 10, 31, 9, 0, 0, 0, time.UTC),
+			Months:         4,
+			ExpectedOutput: time.Date(2027, 2, 28, 9, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			output := AddMonths(scenario.Input, scenario.Months)
+			if !output.Equal(scenario.ExpectedOutput) {
+				t.Errorf("[%s] expected output to be %v, got %v", scenario.Name, scenario.ExpectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestPeriod_AddTo(t *testing.T) {
+	location, _ := time.LoadLocation("America/Montreal")
+	// Daylight saving time ends on 2026-11-01 in Montreal, but adding months should keep the same time of day
+	now := time.Date(2026, 10, 15, 14, 0, 0, 0, location)
+	output := Period{Months: 1, Duration: 2 * time.Hour}.AddTo(now)
+	if expectedOutput := time.Date(2026, 11, 15, 16, 0, 0, 0, location); !output.Equal(expectedOutput) {
+		t.Errorf("expected output to be %v, got %v", expectedOutput, output)
+	}
+}
+
+func TestPrettyPeriod(t *testing.T) {
+	type Scenario struct {
+		Name           string
+		Input          Period
+		ExpectedOutput string
+	}
+	scenarios := []Scenario{
+		{
+			Name:           "1mo",
+			Input:          Period{Months: 1},
+			ExpectedOutput: "1 month",
+		},
+		{
+			Name:           "1y6mo",
+			Input:          Period{Months: 18},
+			ExpectedOutput: "1 year and 6 months",
+		},
+		{
+			Name:           "2y3d",
+			Input:          Period{Months: 24, Duration: 72 * time.Hour},
+			ExpectedOutput: "2 years and 3 days",
+		},
+		{
+			Name:           "12h",
+			Input:          Period{Duration: 12 * time.Hour},
+			ExpectedOutput: "12 hours",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			output := PrettyPeriod(scenario.Input)
+			if output != scenario.ExpectedOutput {
+				t.Errorf("[%s] expected output to be %v, got %v", scenario.Name, scenario.ExpectedOutput, output)
+			}
+		})
+	}
+}
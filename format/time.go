@@ -0,0 +1,191 @@
+package format
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TwiN/discord-reminder-bot/i18n"
+)
+
+// DefaultTimeOfDay is the time of day used when a time expression refers to a day without specifying a time
+const DefaultTimeOfDay = 9 * time.Hour
+
+var ErrInvalidTimeOfDay = i18n.Errorf("invalid time of day, expected something like 15:30, 9am or 3:30pm")
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseTime parses the time expression at the beginning of s and returns the time it refers to, as well as
+// everything in s that comes after the time expression.
+//
+// Supported expressions are relative durations (e.g. "2h30m", "in 2 hours", "1 day 3 hours", "3 months", "dans 2 jours"),
+// clock times (e.g. "at 15:30", "9am"), ISO dates (e.g. "2026-11-03", "2026-11-03 09:00"),
+// weekdays (e.g. "friday 9am", "on fri at 17:00") as well as "today" and "tomorrow" (e.g. "tomorrow at 8am").
+// Absolute times as well as months and years are resolved in the location of now, and clock times that have already
+// passed today refer to the next day.
+func ParseTime(s string, now time.Time) (time.Time, string, error) {
+	token, rest := nextToken(s)
+	token = strings.ToLower(token)
+	switch token {
+	case "in", "dans":
+		period, _, rest, err := ParsePeriodPrefix(rest)
+		if err != nil {
+			return time.Time{}, s, err
+		}
+		return period.AddTo(now), rest, nil
+	case "at":
+		return parseTimeOfDay(rest, now, true)
+	case "on":
+		// e.g. "on friday" or "on 2026-11-03"
+		token, rest = nextToken(rest)
+		token = strings.ToLower(token)
+	case "today":
+		return parseTimeOfDayOrDefault(rest, startOfDay(now))
+	case "tomorrow":
+		if t, restAfterTimeOfDay, err := parseTimeOfDay(rest, startOfDay(now).AddDate(0, 0, 1), false); err == nil {
+			return t, restAfterTimeOfDay, nil
+		}
+		// For backward compatibility, "tomorrow" on its own means in 24 hours
+		return now.Add(24 * time.Hour), rest, nil
+	}
+	if weekday, ok := weekdays[token]; ok {
+		daysUntilWeekday := (int(weekday) - int(now.Weekday()) + 7) % 7
+		day := startOfDay(now).AddDate(0, 0, daysUntilWeekday)
+		t, rest, err := parseTimeOfDayOrDefault(rest, day)
+		if err == nil && !t.After(now) {
+			// The time has already passed today, so we'll assume the user meant next week
+			t = t.AddDate(0, 0, 7)
+		}
+		return t, rest, err
+	}
+	if len(token) >= len("2006-01-02") {
+		if date, err := time.ParseInLocation("2006-01-02", token[:len("2006-01-02")], now.Location()); err == nil {
+			if len(token) > len("2006-01-02") {
+				// e.g. 2026-11-03T09:00
+				if token[len("2006-01-02")] != 't' {
+					return time.Time{}, s, i18n.Errorf("invalid date, expected something like 2026-11-03 or 2026-11-03T09:00")
+				}
+				timeOfDay, err := parseClock(token[len("2006-01-02")+1:])
+				if err != nil {
+					return time.Time{}, s, err
+				}
+				return atTimeOfDay(date, timeOfDay), rest, nil
+			}
+			return parseTimeOfDayOrDefault(rest, date)
+		}
+	}
+	if t, rest, err := parseTimeOfDay(s, now, true); err == nil {
+		return t, rest, nil
+	}
+	period, _, rest, err := ParsePeriodPrefix(s)
+	if err != nil {
+		return time.Time{}, s, err
+	}
+	return period.AddTo(now), rest, nil
+}
+
+// parseTimeOfDayOrDefault parses an optional time of day at the beginning of s and applies it to day.
+// If s doesn't start with a time of day, DefaultTimeOfDay is used instead.
+func parseTimeOfDayOrDefault(s string, day time.Time) (time.Time, string, error) {
+	if t, rest, err := parseTimeOfDay(s, day, false); err == nil {
+		return t, rest, nil
+	} else if token, _ := nextToken(s); strings.ToLower(token) == "at" {
+		// The user explicitly specified a time of day, so we shouldn't silently fall back to the default
+		return time.Time{}, s, err
+	}
+	return atTimeOfDay(day, DefaultTimeOfDay), s, nil
+}
+
+// parseTimeOfDay parses a time of day (e.g. "15:30", "at 9am", "3:30 pm") at the beginning of s and applies it to the
+// day of reference. If nextOccurrence is true and the resulting time is not after reference, the time of day is
+// applied to the day after instead.
+func parseTimeOfDay(s string, reference time.Time, nextOccurrence bool) (time.Time, string, error) {
+	token, rest := nextToken(s)
+	if strings.ToLower(token) == "at" {
+		token, rest = nextToken(rest)
+	}
+	// Support a space between the time and the meridiem, e.g. "3 pm"
+	if meridiem, restAfterMeridiem := nextToken(rest); isMeridiem(meridiem) {
+		token, rest = token+meridiem, restAfterMeridiem
+	}
+	timeOfDay, err := parseClock(token)
+	if err != nil {
+		return time.Time{}, s, err
+	}
+	t := atTimeOfDay(reference, timeOfDay)
+	if nextOccurrence && !t.After(reference) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, rest, nil
+}
+
+// parseClock parses a clock time like "15:30", "9am" or "3:30pm" into the duration since midnight.
+// A bare number (e.g. "15") is not considered to be a clock time, because it would be ambiguous.
+func parseClock(s string) (time.Duration, error) {
+	s = strings.ToLower(s)
+	var meridiem string
+	if len(s) > 2 && isMeridiem(s[len(s)-2:]) {
+		s, meridiem = s[:len(s)-2], s[len(s)-2:]
+	}
+	hoursString, minutesString, hasMinutes := strings.Cut(s, ":")
+	if !hasMinutes && len(meridiem) == 0 {
+		return 0, ErrInvalidTimeOfDay
+	}
+	hours, err := strconv.Atoi(hoursString)
+	if err != nil || hours < 0 || hours > 23 || len(hoursString) > 2 {
+		return 0, ErrInvalidTimeOfDay
+	}
+	var minutes int
+	if hasMinutes {
+		minutes, err = strconv.Atoi(minutesString)
+		if err != nil || minutes < 0 || minutes > 59 || len(minutesString) != 2 {
+			return 0, ErrInvalidTimeOfDay
+		}
+	}
+	if len(meridiem) > 0 {
+		if hours < 1 || hours > 12 {
+			return 0, ErrInvalidTimeOfDay
+		}
+		if hours == 12 {
+			hours = 0
+		}
+		if meridiem == "pm" {
+			hours += 12
+		}
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+func isMeridiem(s string) bool {
+	s = strings.ToLower(s)
+	return s == "am" || s == "pm"
+}
+
+// startOfDay returns midnight of the day of t in the location of t
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// atTimeOfDay returns the time on the day of t at the given time of day, in the location of t
+func atTimeOfDay(t time.Time, timeOfDay time.Duration) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, int(timeOfDay.Hours()), int(timeOfDay.Minutes())%60, 0, 0, t.Location())
+}
+
+// nextToken returns the first whitespace-separated token of s as well as everything that comes after it
+func nextToken(s string) (string, string) {
+	s = strings.TrimLeft(s, " \t\n")
+	if index := strings.IndexAny(s, " \t\n"); index != -1 {
+		return s[:index], s[index:]
+	}
+	return s, ""
+}
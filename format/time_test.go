@@ -0,0 +1,154 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	location, _ := time.LoadLocation("America/Montreal")
+	// Wednesday
+	now := time.Date(2026, 11, 4, 14, 0, 0, 0, location)
+	type Scenario struct {
+		Input          string
+		ExpectedOutput time.Time
+		ExpectedRest   string
+	}
+	scenarios := []Scenario{
+		{
+			Input:          "2h30m note",
+			ExpectedOutput: now.Add(2*time.Hour + 30*time.Minute),
+			ExpectedRest:   " note",
+		},
+		{
+			Input:          "in 2 hours call mom",
+			ExpectedOutput: now.Add(2 * time.Hour),
+			ExpectedRest:   " call mom",
+		},
+		{
+			Input:          "dans 2 jours appeler maman",
+			ExpectedOutput: now.Add(48 * time.Hour),
+			ExpectedRest:   " appeler maman",
+		},
+		{
+			Input:          "1 day 3 hours note",
+			ExpectedOutput: now.Add(27 * time.Hour),
+			ExpectedRest:   " note",
+		},
+		{
+			Input:          "half an hour",
+			ExpectedOutput: now.Add(30 * time.Minute),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "in 3 months renew the contract",
+			ExpectedOutput: time.Date(2027, 2, 4, 14, 0, 0, 0, location),
+			ExpectedRest:   " renew the contract",
+		},
+		{
+			Input:          "1y",
+			ExpectedOutput: time.Date(2027, 11, 4, 14, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "in 2d",
+			ExpectedOutput: now.Add(48 * time.Hour),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "tomorrow",
+			ExpectedOutput: now.Add(24 * time.Hour),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "tomorrow at 8am standup",
+			ExpectedOutput: time.Date(2026, 11, 5, 8, 0, 0, 0, location),
+			ExpectedRest:   " standup",
+		},
+		{
+			Input:          "at 15:30",
+			ExpectedOutput: time.Date(2026, 11, 4, 15, 30, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "at 9am",
+			ExpectedOutput: time.Date(2026, 11, 5, 9, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "3:45 pm meeting",
+			ExpectedOutput: time.Date(2026, 11, 4, 15, 45, 0, 0, location),
+			ExpectedRest:   " meeting",
+		},
+		{
+			Input:          "12am",
+			ExpectedOutput: time.Date(2026, 11, 5, 0, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "today 5pm",
+			ExpectedOutput: time.Date(2026, 11, 4, 17, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "2026-11-03 09:00",
+			ExpectedOutput: time.Date(2026, 11, 3, 9, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "2026-12-25",
+			ExpectedOutput: time.Date(2026, 12, 25, 9, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "2026-12-25T18:15 dinner",
+			ExpectedOutput: time.Date(2026, 12, 25, 18, 15, 0, 0, location),
+			ExpectedRest:   " dinner",
+		},
+		{
+			Input:          "friday 9am",
+			ExpectedOutput: time.Date(2026, 11, 6, 9, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "on Fri at 17:00",
+			ExpectedOutput: time.Date(2026, 11, 6, 17, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "wednesday 10:00",
+			ExpectedOutput: time.Date(2026, 11, 11, 10, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+		{
+			Input:          "monday",
+			ExpectedOutput: time.Date(2026, 11, 9, 9, 0, 0, 0, location),
+			ExpectedRest:   "",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Input, func(t *testing.T) {
+			output, rest, err := ParseTime(scenario.Input, now)
+			if err != nil {
+				t.Fatalf("[%s] expected no error, got %v", scenario.Input, err)
+			}
+			if !output.Equal(scenario.ExpectedOutput) {
+				t.Errorf("[%s] expected output to be %v, got %v", scenario.Input, scenario.ExpectedOutput, output)
+			}
+			if rest != scenario.ExpectedRest {
+				t.Errorf("[%s] expected rest to be '%s', got '%s'", scenario.Input, scenario.ExpectedRest, rest)
+			}
+		})
+	}
+}
+
+func TestParseTimeWithInvalidInput(t *testing.T) {
+	now := time.Now()
+	for _, input := range []string{"", "soon", "at 25:00", "at noon", "friday at 13pm", "2026-13-01", "2026-11-03T9h"} {
+		t.Run(input, func(t *testing.T) {
+			if _, _, err := ParseTime(input, now); err == nil {
+				t.Errorf("[%s] expected an error", input)
+			}
+		})
+	}
+}
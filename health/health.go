@@ -0,0 +1,133 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// MaximumConsecutivePollFailures is the number of times in a row that reminders may fail to be loaded from the
+	// database before the bot is considered unhealthy
+	MaximumConsecutivePollFailures = 5
+
+	// MaximumGatewayDisconnection is how long the bot may stay disconnected from Discord's gateway before it is
+	// considered unhealthy. Shorter disconnections only make the bot unready, since the session reconnects by itself.
+	MaximumGatewayDisconnection = 5 * time.Minute
+)
+
+// Report is the response of the health endpoints
+type Report struct {
+	// Status is "ok" if the check passed, or "unavailable" otherwise
+	Status string `json:"status"`
+
+	// Problems are the reasons why the check did not pass
+	Problems []string `json:"problems,omitempty"`
+
+	GatewayConnected        bool       `json:"gatewayConnected"`
+	GatewayStateChangedAt   *time.Time `json:"gatewayStateChangedAt,omitempty"`
+	LastPollAt              *time.Time `json:"lastPollAt,omitempty"`
+	SecondsSinceLastPoll    *float64   `json:"secondsSinceLastPoll,omitempty"`
+	ConsecutivePollFailures int        `json:"consecutivePollFailures"`
+	DatabaseReachable       *bool      `json:"databaseReachable,omitempty"`
+}
+
+// Status is the state of the connection to Discord's gateway and of the worker delivering reminders
+type Status struct {
+	StartedAt               time.Time
+	GatewayConnected        bool
+	GatewayStateChangedAt   time.Time
+	LastPollAt              time.Time
+	ConsecutivePollFailures int
+}
+
+// Checker checks the health of the bot
+type Checker struct {
+	// Status returns the state of the connection to Discord's gateway and of the worker delivering reminders
+	Status func() Status
+
+	// MaximumTimeSincePoll is how long the bot may go without loading reminders from the database before it is
+	// considered unhealthy
+	MaximumTimeSincePoll time.Duration
+
+	// Ping returns an error if the database is not reachable
+	Ping func() error
+}
+
+// LivenessHandler serves /healthz, which fails if the bot is stuck and should be restarted: when it has been
+// disconnected from the gateway for too long, or when reminders can no longer be loaded from the database
+func (checker *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writeReport(writer, checker.Check(false))
+	})
+}
+
+// ReadinessHandler serves /readyz, which fails if the bot is not able to handle commands and deliver reminders right
+// now: when it hasn't started yet, when it is disconnected from the gateway or when the database is unreachable
+func (checker *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writeReport(writer, checker.Check(true))
+	})
+}
+
+// Check returns the liveness of the bot or, if readiness is true, its readiness
+func (checker *Checker) Check(readiness bool) *Report {
+	status := checker.Status()
+	report := &Report{
+		GatewayConnected:        status.GatewayConnected,
+		ConsecutivePollFailures: status.ConsecutivePollFailures,
+	}
+	if !status.GatewayStateChangedAt.IsZero() {
+		report.GatewayStateChangedAt = &status.GatewayStateChangedAt
+	}
+	if !status.LastPollAt.IsZero() {
+		secondsSinceLastPoll := time.Since(status.LastPollAt).Seconds()
+		report.LastPollAt, report.SecondsSinceLastPoll = &status.LastPollAt, &secondsSinceLastPoll
+	}
+	if !status.StartedAt.IsZero() {
+		if !status.GatewayConnected && time.Since(status.GatewayStateChangedAt) > MaximumGatewayDisconnection {
+			report.Problems = append(report.Problems, fmt.Sprintf("disconnected from the gateway for %s", time.Since(status.GatewayStateChangedAt).Round(time.Second)))
+		}
+		if status.ConsecutivePollFailures >= MaximumConsecutivePollFailures {
+			report.Problems = append(report.Problems, fmt.Sprintf("failed to load reminders from the database %d times in a row", status.ConsecutivePollFailures))
+		}
+		lastPollAt := status.LastPollAt
+		if lastPollAt.IsZero() {
+			lastPollAt = status.StartedAt
+		}
+		if time.Since(lastPollAt) > checker.MaximumTimeSincePoll {
+			report.Problems = append(report.Problems, fmt.Sprintf("reminders have not been loaded from the database for %s", time.Since(lastPollAt).Round(time.Second)))
+		}
+	}
+	if readiness {
+		if status.StartedAt.IsZero() {
+			report.Problems = append(report.Problems, "not started yet")
+		} else if !status.GatewayConnected && time.Since(status.GatewayStateChangedAt) <= MaximumGatewayDisconnection {
+			report.Problems = append(report.Problems, "disconnected from the gateway")
+		}
+		// The database is only pinged for readiness, since an unreachable database doesn't require a restart
+		pingErr := checker.Ping()
+		databaseReachable := pingErr == nil
+		report.DatabaseReachable = &databaseReachable
+		if pingErr != nil {
+			report.Problems = append(report.Problems, "database is unreachable: "+pingErr.Error())
+		}
+	}
+	if len(report.Problems) > 0 {
+		report.Status = "unavailable"
+	} else {
+		report.Status = "ok"
+	}
+	return report
+}
+
+func writeReport(writer http.ResponseWriter, report *Report) {
+	writer.Header().Set("Content-Type", "application/json")
+	if len(report.Problems) > 0 {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		writer.WriteHeader(http.StatusOK)
+	}
+	_ = json.NewEncoder(writer).Encode(report)
+}
@@ -0,0 +1,148 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChecker(t *testing.T) {
+	const maximumTimeSincePoll = time.Hour
+	now := time.Now()
+	healthy := Status{
+		StartedAt:             now.Add(-time.Hour),
+		GatewayConnected:      true,
+		GatewayStateChangedAt: now.Add(-time.Hour),
+		LastPollAt:            now.Add(-time.Minute),
+	}
+	scenarios := []struct {
+		Name                   string
+		Status                 Status
+		PingErr                error
+		ExpectedLivenessCode   int
+		ExpectedReadinessCode  int
+		ExpectedLivenessIssues int
+	}{
+		{
+			Name:                  "healthy",
+			Status:                healthy,
+			ExpectedLivenessCode:  http.StatusOK,
+			ExpectedReadinessCode: http.StatusOK,
+		},
+		{
+			Name:                  "not-started",
+			Status:                Status{},
+			ExpectedLivenessCode:  http.StatusOK,
+			ExpectedReadinessCode: http.StatusServiceUnavailable,
+		},
+		{
+			Name: "briefly-disconnected",
+			Status: Status{
+				StartedAt:             healthy.StartedAt,
+				GatewayConnected:      false,
+				GatewayStateChangedAt: now.Add(-time.Minute),
+				LastPollAt:            healthy.LastPollAt,
+			},
+			ExpectedLivenessCode:  http.StatusOK,
+			ExpectedReadinessCode: http.StatusServiceUnavailable,
+		},
+		{
+			Name: "disconnected-for-too-long",
+			Status: Status{
+				StartedAt:             healthy.StartedAt,
+				GatewayConnected:      false,
+				GatewayStateChangedAt: now.Add(-MaximumGatewayDisconnection - time.Minute),
+				LastPollAt:            healthy.LastPollAt,
+			},
+			ExpectedLivenessCode:   http.StatusServiceUnavailable,
+			ExpectedReadinessCode:  http.StatusServiceUnavailable,
+			ExpectedLivenessIssues: 1,
+		},
+		{
+			Name: "consecutive-poll-failures",
+			Status: Status{
+				StartedAt:               healthy.StartedAt,
+				GatewayConnected:        true,
+				GatewayStateChangedAt:   healthy.GatewayStateChangedAt,
+				LastPollAt:              healthy.LastPollAt,
+				ConsecutivePollFailures: MaximumConsecutivePollFailures,
+			},
+			ExpectedLivenessCode:   http.StatusServiceUnavailable,
+			ExpectedReadinessCode:  http.StatusServiceUnavailable,
+			ExpectedLivenessIssues: 1,
+		},
+		{
+			Name: "worker-stuck",
+			Status: Status{
+				StartedAt:             now.Add(-24 * time.Hour),
+				GatewayConnected:      true,
+				GatewayStateChangedAt: healthy.GatewayStateChangedAt,
+				LastPollAt:            now.Add(-maximumTimeSincePoll - time.Minute),
+			},
+			ExpectedLivenessCode:   http.StatusServiceUnavailable,
+			ExpectedReadinessCode:  http.StatusServiceUnavailable,
+			ExpectedLivenessIssues: 1,
+		},
+		{
+			Name: "never-polled",
+			Status: Status{
+				StartedAt:             now.Add(-maximumTimeSincePoll - time.Minute),
+				GatewayConnected:      true,
+				GatewayStateChangedAt: healthy.GatewayStateChangedAt,
+			},
+			ExpectedLivenessCode:   http.StatusServiceUnavailable,
+			ExpectedReadinessCode:  http.StatusServiceUnavailable,
+			ExpectedLivenessIssues: 1,
+		},
+		{
+			Name:                  "database-unreachable",
+			Status:                healthy,
+			PingErr:               errors.New("database is locked"),
+			ExpectedLivenessCode:  http.StatusOK,
+			ExpectedReadinessCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			pinged := false
+			checker := &Checker{
+				Status:               func() Status { return scenario.Status },
+				MaximumTimeSincePoll: maximumTimeSincePoll,
+				Ping: func() error {
+					pinged = true
+					return scenario.PingErr
+				},
+			}
+			recorder := httptest.NewRecorder()
+			checker.LivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if recorder.Code != scenario.ExpectedLivenessCode {
+				t.Errorf("expected liveness to return %d, got %d", scenario.ExpectedLivenessCode, recorder.Code)
+			}
+			var report Report
+			if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+				t.Fatal("expected a JSON report, got", recorder.Body.String())
+			}
+			if len(report.Problems) != scenario.ExpectedLivenessIssues {
+				t.Errorf("expected %d problems, got %v", scenario.ExpectedLivenessIssues, report.Problems)
+			}
+			if pinged || report.DatabaseReachable != nil {
+				t.Error("expected liveness not to ping the database")
+			}
+			recorder = httptest.NewRecorder()
+			checker.ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if recorder.Code != scenario.ExpectedReadinessCode {
+				t.Errorf("expected readiness to return %d, got %d", scenario.ExpectedReadinessCode, recorder.Code)
+			}
+			report = Report{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+				t.Fatal("expected a JSON report, got", recorder.Body.String())
+			}
+			if report.DatabaseReachable == nil || *report.DatabaseReachable != (scenario.PingErr == nil) {
+				t.Error("expected readiness report to tell whether the database is reachable")
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package i18n
+
+var germanCatalog = &catalog{
+	name:       "Deutsch",
+	isSingular: func(count int) bool { return count == 1 },
+	messages: map[string]string{
+		// Languages
+		"English": "Englisch",
+		"French":  "Französisch",
+		"German":  "Deutsch",
+
+		// Durations
+		"and": "und",
+
+		"invalid duration, expected something like 2h30m, 90 seconds or 1 day 3 hours": "ungültige Dauer, versuche etwas wie 2h30m, 90 Sekunden oder 1 Tag 3 Stunden",
+		"months and years do not have a fixed duration":                                "Monate und Jahre haben keine feste Dauer",
+		"duration is too long": "die Dauer ist zu lang",
+		"invalid time of day, expected something like 15:30, 9am or 3:30pm":    "ungültige Uhrzeit, versuche etwas wie 15:30, 9am oder 3:30pm",
+		"invalid date, expected something like 2026-11-03 or 2026-11-03T09:00": "ungültiges Datum, versuche etwas wie 2026-11-03 oder 2026-11-03T09:00",
+
+		// Reminders
+		"I will remind %s%s about [this message](%s) at %s":  "Ich werde %s%s am %[4]s an [diese Nachricht](%[3]s) erinnern",
+		"I will remind you%s about [this message](%s) at %s": "Ich werde dich%s am %[3]s an [diese Nachricht](%[2]s) erinnern",
+		"I will remind %s%s about [this message](%s) in %s":  "Ich werde %s%s in %[4]s an [diese Nachricht](%[3]s) erinnern",
+		"I will remind you%s about [this message](%s) in %s": "Ich werde dich%s in %[3]s an [diese Nachricht](%[2]s) erinnern",
+		" in <#%s>":                 " in <#%s>",
+		", and every %s after that": ", und danach alle %s",
+		" until %s":                 " bis %s",
+		" (last occurrence)":        " (letzte Wiederholung)",
+		" (%d occurrences left)":    " (noch %d Wiederholungen)",
+		"<@%s> asked me to remind you about [this message](%s) and attached the following note:\n```%s```": "<@%s> hat mich gebeten, dich an [diese Nachricht](%s) zu erinnern, und folgende Notiz angehängt:\n```%s```",
+		"You asked me to remind you about [this message](%s) and attached the following note:\n```%s```":   "Du hast mich gebeten, dich an [diese Nachricht](%s) zu erinnern, und folgende Notiz angehängt:\n```%s```",
+		"<@%s> asked me to remind you about this message: [this message](%s)":                              "<@%s> hat mich gebeten, dich an diese Nachricht zu erinnern: [diese Nachricht](%s)",
+		"You asked me to remind you about this message: [this message](%s)":                                "Du hast mich gebeten, dich an diese Nachricht zu erinnern: [diese Nachricht](%s)",
+		"Attachment":   "Anhang",
+		" in #%s":      " in #%s",
+		"Edit":         "Bearbeiten",
+		"Message link": "Nachrichtenlink",
+		"I was unable to send you this reminder by direct message, so here it is.\n\n": "Ich konnte dir diese Erinnerung nicht per Direktnachricht schicken, deshalb kommt sie hier.\n\n",
+		" This reminder could not be delivered.":                                       " Diese Erinnerung konnte nicht zugestellt werden.",
+
+		// Components
+		"Refresh":  "Aktualisieren",
+		"Delete":   "Löschen",
+		"Custom":   "Andere",
+		"Previous": "Zurück",
+		"Next":     "Weiter",
+		"10m":      "10 Min.",
+		"1h":       "1 Std.",
+		"3h":       "3 Std.",
+		"Tomorrow": "Morgen",
+
+		"Snooze reminder": "Erinnerung verschieben",
+		"Until when should the reminder be snoozed?":            "Bis wann soll die Erinnerung verschoben werden?",
+		"e.g. 30m, 2h, at 15:30, tomorrow at 8am or friday 9am": "z. B. 30m, 2h, at 15:30, tomorrow at 8am oder friday 9am",
+		"This reminder can no longer be snoozed":                "Diese Erinnerung kann nicht mehr verschoben werden",
+		"Snoozed until %s":                                      "Verschoben bis %s",
+		"Monday, January 2 at 15:04 MST":                        "02.01. um 15:04 MST",
+
+		// List of reminders
+		"#%d · In %s from now":                        "#%d · In %s",
+		" (repeats every %s)":                         " (wiederholt sich alle %s)",
+		"_No reminders to display_":                   "_Keine Erinnerungen vorhanden_",
+		"Below is a list of all reminders on page %d": "Hier ist die Liste aller Erinnerungen auf Seite %d",
+		"List of Reminders":                           "Liste der Erinnerungen",
+		"Page %d out of %d":                           "Seite %d von %d",
+
+		// Errors
+		"Error: %s":                                                                    "Fehler: %s",
+		"Error: failed to retrieve reminder":                                           "Fehler: Erinnerung konnte nicht abgerufen werden",
+		"Error: failed to update reminder":                                             "Fehler: Erinnerung konnte nicht aktualisiert werden",
+		"Error: failed to open direct message":                                         "Fehler: Direktnachricht konnte nicht geöffnet werden",
+		"Error: failed to retrieve your reminders":                                     "Fehler: deine Erinnerungen konnten nicht abgerufen werden",
+		"Error: failed to retrieve your permissions":                                   "Fehler: deine Berechtigungen konnten nicht abgerufen werden",
+		"Error: failed to retrieve the settings of the user to remind":                 "Fehler: die Einstellungen des zu erinnernden Benutzers konnten nicht abgerufen werden",
+		"Unexpected text after the time: `%s`":                                         "Unerwarteter Text nach der Zeit: `%s`",
+		"unexpected text after the interval: %s":                                       "unerwarteter Text nach dem Intervall: %s",
+		"Reminder #%d has been deleted.":                                               "Erinnerung #%d wurde gelöscht.",
+		"You have no reminder with ID %d.":                                             "Du hast keine Erinnerung mit der ID %d.",
+		"Note must have less than %d characters":                                       "Die Notiz muss weniger als %d Zeichen haben",
+		"You have reached the maximum number of reminders a single user can have (%d)": "Du hast die maximale Anzahl an Erinnerungen pro Benutzer erreicht (%d)",
+		"Invalid repeat interval: ```%s```\n:information_source: _Try something like `every 24h`, `every 7d until 2030-12-31` or `every 1d 10 times`._":                     "Ungültiges Wiederholungsintervall: ```%s```\n:information_source: _Versuche etwas wie `every 24h`, `every 7d until 2030-12-31` oder `every 1d 10 times`._",
+		"Invalid time format: ```%s```\n:information_source: _Try something like `45m`, `5h30m`, `7d`, `at 15:30`, `tomorrow at 8am`, `friday 9am` or `2030-11-03 09:00`._": "Ungültiges Zeitformat: ```%s```\n:information_source: _Versuche etwas wie `45m`, `5h30m`, `7d`, `at 15:30`, `tomorrow at 8am`, `friday 9am` oder `2030-11-03 09:00`._",
+		"Reminder must be due in between %s and %s from now":          "Die Erinnerung muss zwischen %s und %s in der Zukunft liegen",
+		"interval must be between %s and %s":                          "das Intervall muss zwischen %s und %s liegen",
+		"end date must have the format YYYY-MM-DD":                    "das Enddatum muss das Format JJJJ-MM-TT haben",
+		"end date must be after the first occurrence of the reminder": "das Enddatum muss nach dem ersten Termin der Erinnerung liegen",
+		"number of occurrences must be at least 1":                    "die Anzahl der Wiederholungen muss mindestens 1 sein",
+
+		// Validation
+		"Reminders can only be delivered in a channel when they are created from a server":                                   "Erinnerungen können nur in einem Kanal zugestellt werden, wenn sie auf einem Server erstellt werden",
+		"Channel <#%s> does not exist or is not visible to me":                                                               "Der Kanal <#%s> existiert nicht oder ist für mich nicht sichtbar",
+		"Channel <#%s> must be in this server":                                                                               "Der Kanal <#%s> muss zu diesem Server gehören",
+		"You are not allowed to send messages in <#%s>":                                                                      "Du darfst keine Nachrichten in <#%s> senden",
+		"I am not allowed to send messages in <#%s>":                                                                         "Ich darf keine Nachrichten in <#%s> senden",
+		"Invalid message link. You can get the link of a message by right-clicking on it and selecting `Copy Message Link`.": "Ungültiger Nachrichtenlink. Du erhältst den Link einer Nachricht, indem du mit der rechten Maustaste darauf klickst und `Nachrichtenlink kopieren` auswählst.",
+		"The only direct messages I can remind you about are the ones we exchanged":                                          "Ich kann dich nur an Direktnachrichten erinnern, die wir miteinander ausgetauscht haben",
+		"The message linked does not exist or is not visible to me":                                                          "Die verlinkte Nachricht existiert nicht oder ist für mich nicht sichtbar",
+		"You are not allowed to see the message linked":                                                                      "Du darfst die verlinkte Nachricht nicht sehen",
+		"You can only remind other users and roles from a server":                                                            "Du kannst andere Benutzer und Rollen nur auf einem Server erinnern",
+		"You are not allowed to remind other users and roles in this server":                                                 "Du darfst auf diesem Server keine anderen Benutzer und Rollen erinnern",
+		"Role <@&%s> does not exist in this server":                                                                          "Die Rolle <@&%s> existiert auf diesem Server nicht",
+		"You are not allowed to mention <@&%s>":                                                                              "Du darfst <@&%s> nicht erwähnen",
+		"<@%s> is not a member of this server":                                                                               "<@%s> ist kein Mitglied dieses Servers",
+		"Bots cannot be reminded":                                                                                            "Bots können nicht erinnert werden",
+		"<@%s> does not accept reminders from you":                                                                           "<@%s> nimmt keine Erinnerungen von dir an",
+
+		// Commands
+		"**Usage:**\n```%sRemindMe [@USER | @ROLE] TIME [every INTERVAL [until YYYY-MM-DD | N times]] [--channel #CHANNEL] [MESSAGE_LINK] NOTE```**Where:**\n- `@USER` or `@ROLE` is an optional user or role to remind instead of you, if you are allowed to remind others\n- `TIME` is either a duration with a format similar to the following: `30m`, `2h`, `6h30m`, `7d12h30m`, `2 hours` or `1 day 3 hours`, or an absolute time like `at 15:30`, `tomorrow at 8am`, `friday 9am` or `2030-11-03 09:00`\n- `INTERVAL` is an optional interval at which the reminder should repeat (e.g. `24h`, `7d`, `1 month`), optionally followed by an end date or a number of occurrences\n- `#CHANNEL` is an optional channel in which the reminder should be delivered instead of being sent to you by direct message. Use `%sRemindHere` to deliver the reminder in the current channel\n- `MESSAGE_LINK` is an optional link to the message to be reminded about. If you use the command in reply to a message, you are reminded about that message instead of your command\n- `NOTE` is an optional note to attach to the reminder with less than %d characters\n\n:information_source: _You can also create a reminder by reacting to a message with any of %s, and you can view your reminders by using `%slist`. Absolute times are interpreted in the timezone set with `%stimezone`._": "**Verwendung:**\n```%sRemindMe [@BENUTZER | @ROLLE] ZEIT [every INTERVALL [until JJJJ-MM-TT | N times]] [--channel #KANAL] [NACHRICHTENLINK] NOTIZ```**Wobei:**\n- `@BENUTZER` oder `@ROLLE` ein optionaler Benutzer oder eine optionale Rolle ist, die statt dir erinnert werden soll, sofern du andere erinnern darfst\n- `ZEIT` entweder eine Dauer in einem Format wie `30m`, `2h`, `6h30m`, `7d12h30m`, `2 Stunden` oder `1 Tag 3 Stunden` ist, oder eine absolute Zeit wie `at 15:30`, `tomorrow at 8am`, `friday 9am` oder `2030-11-03 09:00`\n- `INTERVALL` ein optionales Intervall ist, in dem sich die Erinnerung wiederholen soll (z. B. `24h`, `7d`, `1 Monat`), optional gefolgt von einem Enddatum oder einer Anzahl an Wiederholungen\n- `#KANAL` ein optionaler Kanal ist, in dem die Erinnerung zugestellt werden soll, statt sie dir per Direktnachricht zu schicken. Verwende `%sRemindHere`, um die Erinnerung im aktuellen Kanal zuzustellen\n- `NACHRICHTENLINK` ein optionaler Link zu der Nachricht ist, an die erinnert werden soll. Wenn du den Befehl als Antwort auf eine Nachricht verwendest, wirst du an diese Nachricht statt an deinen Befehl erinnert\n- `NOTIZ` eine optionale Notiz mit weniger als %d Zeichen ist, die an die Erinnerung angehängt wird\n\n:information_source: _Du kannst eine Erinnerung auch erstellen, indem du mit einem von %s auf eine Nachricht reagierst, und deine Erinnerungen mit `%slist` anzeigen. Absolute Zeiten werden in der mit `%stimezone` festgelegten Zeitzone interpretiert._",
+		"Your timezone is currently `%s`.\n**Usage:**\n```%sTimezone AREA/CITY```**Where:**\n- `AREA/CITY` is the name of a timezone from the [tz database](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones), e.g. `America/Montreal` or `Europe/Paris`\n\n:information_source: _Your timezone is used to interpret absolute times like `at 15:30` and to display the time at which your reminders are due._": "Deine Zeitzone ist derzeit `%s`.\n**Verwendung:**\n```%sTimezone REGION/STADT```**Wobei:**\n- `REGION/STADT` der Name einer Zeitzone aus der [tz-Datenbank](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones) ist, z. B. `America/Montreal` oder `Europe/Berlin`\n\n:information_source: _Deine Zeitzone wird verwendet, um absolute Zeiten wie `at 15:30` zu interpretieren und anzuzeigen, wann deine Erinnerungen fällig sind._",
+		"Unknown timezone `%s`\n:information_source: _Try something like `America/Montreal`, `Europe/Paris` or `UTC`. The full list of timezones is available [here](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones)._":                                                                                                                                                                                      "Unbekannte Zeitzone `%s`\n:information_source: _Versuche etwas wie `America/Montreal`, `Europe/Berlin` oder `UTC`. Die vollständige Liste der Zeitzonen findest du [hier](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones)._",
+		"Your timezone has been set to `%s`, where it is currently %s.": "Deine Zeitzone wurde auf `%s` gesetzt, wo es gerade %s ist.",
+		"Monday 15:04": "15:04 Uhr",
+		"**Usage:**\n```%sBlock @USER\n%sUnblock @USER```Blocked users can't create reminders for you.":                                                                                                                       "**Verwendung:**\n```%sBlock @BENUTZER\n%sUnblock @BENUTZER```Blockierte Benutzer können keine Erinnerungen für dich erstellen.",
+		"Other users can no longer create reminders for you. Use `%sOptIn` to allow them again.":                                                                                                                              "Andere Benutzer können keine Erinnerungen mehr für dich erstellen. Verwende `%sOptIn`, um es ihnen wieder zu erlauben.",
+		"Other users can now create reminders for you, unless you blocked them. Use `%sOptOut` to prevent them from doing so.":                                                                                                "Andere Benutzer können jetzt Erinnerungen für dich erstellen, sofern du sie nicht blockiert hast. Verwende `%sOptOut`, um das zu verhindern.",
+		"**Usage:**\n```%sRemindPermission @ROLE | everyone | reset```Members who can manage messages can always remind other users and roles. This command allows members with another role, or everyone, to do so as well.": "**Verwendung:**\n```%sRemindPermission @ROLLE | everyone | reset```Mitglieder, die Nachrichten verwalten können, dürfen immer andere Benutzer und Rollen erinnern. Mit diesem Befehl dürfen das auch Mitglieder mit einer anderen Rolle oder alle Mitglieder.",
+		"I am currently responding to you in %s.\n**Usage:**\n```%sLanguage LANGUAGE | reset\n%sLanguage server LANGUAGE | reset```**Where:**\n- `LANGUAGE` is one of %s\n\n:information_source: _The language of the server is used for members who did not select a language themselves, and can only be changed by members who can manage the server._": "Ich antworte dir derzeit auf %s.\n**Verwendung:**\n```%sLanguage SPRACHE | reset\n%sLanguage server SPRACHE | reset```**Wobei:**\n- `SPRACHE` eine der folgenden Sprachen ist: %s\n\n:information_source: _Die Sprache des Servers wird für Mitglieder verwendet, die selbst keine Sprache ausgewählt haben, und kann nur von Mitgliedern geändert werden, die den Server verwalten können._",
+		"Unknown language `%s`, expected one of %s":                       "Unbekannte Sprache `%s`, versuche eine der folgenden Sprachen: %s",
+		"I will now respond to you in %s.":                                "Ich antworte dir ab jetzt auf %s.",
+		"This command can only be used in a server.":                      "Dieser Befehl kann nur auf einem Server verwendet werden.",
+		"You must have the Manage Server permission to use this command.": "Du benötigst die Berechtigung Server verwalten, um diesen Befehl zu verwenden.",
+
+		// Configuration
+		"Reminders created from this server can only be delivered by direct message": "Erinnerungen, die auf diesem Server erstellt werden, können nur per Direktnachricht zugestellt werden",
+		"I cannot be used in <#%s>":                                         "Ich kann in <#%s> nicht verwendet werden",
+		"I cannot be used in this channel":                                  "Ich kann in diesem Kanal nicht verwendet werden",
+		"The prefix must have between 1 and %d characters, without spaces":  "Das Präfix muss zwischen 1 und %d Zeichen ohne Leerzeichen haben",
+		"The maximum number of reminders per user must be between 1 and %d": "Die maximale Anzahl an Erinnerungen pro Benutzer muss zwischen 1 und %d liegen",
+		"all channels": "alle Kanäle",
+		"not set":      "nicht festgelegt",
+		"**Configuration of this server:**\n- `prefix`: `%s`\n- `reaction-duration`: %s\n- `max-reminders`: %d\n- `channels`: %s\n- `channel-delivery`: %s\n- `language`: %s": "**Konfiguration dieses Servers:**\n- `prefix`: `%s`\n- `reaction-duration`: %s\n- `max-reminders`: %d\n- `channels`: %s\n- `channel-delivery`: %s\n- `language`: %s",
+		"**Usage:**\n```%[1]sConfig prefix PREFIX | reset\n%[1]sConfig reaction-duration DURATION | reset\n%[1]sConfig max-reminders NUMBER | reset\n%[1]sConfig channels #CHANNEL... | reset\n%[1]sConfig channel-delivery on | off\n%[1]sConfig language LANGUAGE | reset```**Where:**\n- `prefix` is the prefix of my commands in this server\n- `reaction-duration` is in how long reminders created by reacting to a message are due\n- `max-reminders` is the maximum number of reminders a member can have\n- `channels` are the only channels in which I can be used, but `%[1]sConfig` can be used anywhere\n- `channel-delivery` is whether reminders can be delivered in a channel rather than by direct message": "**Verwendung:**\n```%[1]sConfig prefix PRÄFIX | reset\n%[1]sConfig reaction-duration DAUER | reset\n%[1]sConfig max-reminders ANZAHL | reset\n%[1]sConfig channels #KANAL... | reset\n%[1]sConfig channel-delivery on | off\n%[1]sConfig language SPRACHE | reset```**Wobei:**\n- `prefix` das Präfix meiner Befehle auf diesem Server ist\n- `reaction-duration` angibt, wann Erinnerungen fällig sind, die durch eine Reaktion auf eine Nachricht erstellt werden\n- `max-reminders` die maximale Anzahl an Erinnerungen ist, die ein Mitglied haben kann\n- `channels` die einzigen Kanäle sind, in denen ich verwendet werden kann, wobei `%[1]sConfig` überall verwendet werden kann\n- `channel-delivery` angibt, ob Erinnerungen in einem Kanal statt per Direktnachricht zugestellt werden können",
+	},
+	plurals: map[string][2]string{
+		"%d year":   {"%d Jahr", "%d Jahre"},
+		"%d month":  {"%d Monat", "%d Monate"},
+		"%d day":    {"%d Tag", "%d Tage"},
+		"%d hour":   {"%d Stunde", "%d Stunden"},
+		"%d minute": {"%d Minute", "%d Minuten"},
+		"%d second": {"%d Sekunde", "%d Sekunden"},
+	},
+}
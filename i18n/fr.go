@@ -0,0 +1,144 @@
+package i18n
+
+var frenchCatalog = &catalog{
+	name: "Français",
+	// In French, 0 and 1 both take the singular, e.g. "0 jour"
+	isSingular: func(count int) bool { return count == 0 || count == 1 },
+	messages: map[string]string{
+		// Languages
+		"English": "Anglais",
+		"French":  "Français",
+		"German":  "Allemand",
+
+		// Durations
+		"and": "et",
+
+		"invalid duration, expected something like 2h30m, 90 seconds or 1 day 3 hours": "durée invalide, essayez quelque chose comme 2h30m, 90 secondes ou 1 jour 3 heures",
+		"months and years do not have a fixed duration":                                "les mois et les années n'ont pas de durée fixe",
+		"duration is too long": "la durée est trop longue",
+		"invalid time of day, expected something like 15:30, 9am or 3:30pm":    "heure invalide, essayez quelque chose comme 15:30, 9am ou 3:30pm",
+		"invalid date, expected something like 2026-11-03 or 2026-11-03T09:00": "date invalide, essayez quelque chose comme 2026-11-03 ou 2026-11-03T09:00",
+
+		// Reminders
+		"I will remind %s%s about [this message](%s) at %s":  "Je rappellerai [ce message](%[3]s) à %[1]s%[2]s le %[4]s",
+		"I will remind you%s about [this message](%s) at %s": "Je vous rappellerai%s [ce message](%s) le %s",
+		"I will remind %s%s about [this message](%s) in %s":  "Je rappellerai [ce message](%[3]s) à %[1]s%[2]s dans %[4]s",
+		"I will remind you%s about [this message](%s) in %s": "Je vous rappellerai%s [ce message](%s) dans %s",
+		" in <#%s>":                 " dans <#%s>",
+		", and every %s after that": ", puis tous les %s",
+		" until %s":                 " jusqu'au %s",
+		" (last occurrence)":        " (dernière occurrence)",
+		" (%d occurrences left)":    " (%d occurrences restantes)",
+		"<@%s> asked me to remind you about [this message](%s) and attached the following note:\n```%s```": "<@%s> m'a demandé de vous rappeler [ce message](%s) et y a joint la note suivante :\n```%s```",
+		"You asked me to remind you about [this message](%s) and attached the following note:\n```%s```":   "Vous m'avez demandé de vous rappeler [ce message](%s) et y avez joint la note suivante :\n```%s```",
+		"<@%s> asked me to remind you about this message: [this message](%s)":                              "<@%s> m'a demandé de vous rappeler ce message : [ce message](%s)",
+		"You asked me to remind you about this message: [this message](%s)":                                "Vous m'avez demandé de vous rappeler ce message : [ce message](%s)",
+		"Attachment":   "Pièce jointe",
+		" in #%s":      " dans #%s",
+		"Edit":         "Modifier",
+		"Message link": "Lien du message",
+		"I was unable to send you this reminder by direct message, so here it is.\n\n": "Je n'ai pas pu vous envoyer ce rappel par message privé, alors le voici.\n\n",
+		" This reminder could not be delivered.":                                       " Ce rappel n'a pas pu être envoyé.",
+
+		// Components
+		"Refresh":  "Actualiser",
+		"Delete":   "Supprimer",
+		"Custom":   "Autre",
+		"Previous": "Précédent",
+		"Next":     "Suivant",
+		"10m":      "10 min",
+		"1h":       "1 h",
+		"3h":       "3 h",
+		"Tomorrow": "Demain",
+
+		"Snooze reminder": "Reporter le rappel",
+		"Until when should the reminder be snoozed?":            "Jusqu'à quand le rappel doit-il être reporté ?",
+		"e.g. 30m, 2h, at 15:30, tomorrow at 8am or friday 9am": "p. ex. 30m, 2h, at 15:30, tomorrow at 8am ou friday 9am",
+		"This reminder can no longer be snoozed":                "Ce rappel ne peut plus être reporté",
+		"Snoozed until %s":                                      "Reporté jusqu'au %s",
+		"Monday, January 2 at 15:04 MST":                        "02/01 à 15:04 MST",
+
+		// List of reminders
+		"#%d · In %s from now":                        "#%d · Dans %s",
+		" (repeats every %s)":                         " (se répète tous les %s)",
+		"_No reminders to display_":                   "_Aucun rappel à afficher_",
+		"Below is a list of all reminders on page %d": "Voici la liste des rappels de la page %d",
+		"List of Reminders":                           "Liste des rappels",
+		"Page %d out of %d":                           "Page %d sur %d",
+
+		// Errors
+		"Error: %s":                                                                    "Erreur : %s",
+		"Error: failed to retrieve reminder":                                           "Erreur : impossible de récupérer le rappel",
+		"Error: failed to update reminder":                                             "Erreur : impossible de mettre à jour le rappel",
+		"Error: failed to open direct message":                                         "Erreur : impossible d'ouvrir un message privé",
+		"Error: failed to retrieve your reminders":                                     "Erreur : impossible de récupérer vos rappels",
+		"Error: failed to retrieve your permissions":                                   "Erreur : impossible de récupérer vos permissions",
+		"Error: failed to retrieve the settings of the user to remind":                 "Erreur : impossible de récupérer les paramètres de l'utilisateur à qui envoyer le rappel",
+		"Unexpected text after the time: `%s`":                                         "Texte inattendu après l'heure : `%s`",
+		"unexpected text after the interval: %s":                                       "texte inattendu après l'intervalle : %s",
+		"Reminder #%d has been deleted.":                                               "Le rappel #%d a été supprimé.",
+		"You have no reminder with ID %d.":                                             "Vous n'avez aucun rappel avec l'ID %d.",
+		"Note must have less than %d characters":                                       "La note doit contenir moins de %d caractères",
+		"You have reached the maximum number of reminders a single user can have (%d)": "Vous avez atteint le nombre maximal de rappels par utilisateur (%d)",
+		"Invalid repeat interval: ```%s```\n:information_source: _Try something like `every 24h`, `every 7d until 2030-12-31` or `every 1d 10 times`._":                     "Intervalle de répétition invalide : ```%s```\n:information_source: _Essayez quelque chose comme `every 24h`, `every 7d until 2030-12-31` ou `every 1d 10 times`._",
+		"Invalid time format: ```%s```\n:information_source: _Try something like `45m`, `5h30m`, `7d`, `at 15:30`, `tomorrow at 8am`, `friday 9am` or `2030-11-03 09:00`._": "Format d'heure invalide : ```%s```\n:information_source: _Essayez quelque chose comme `45m`, `5h30m`, `7d`, `at 15:30`, `tomorrow at 8am`, `friday 9am` ou `2030-11-03 09:00`._",
+		"Reminder must be due in between %s and %s from now":          "Le rappel doit être prévu dans un délai compris entre %s et %s",
+		"interval must be between %s and %s":                          "l'intervalle doit être compris entre %s et %s",
+		"end date must have the format YYYY-MM-DD":                    "la date de fin doit avoir le format AAAA-MM-JJ",
+		"end date must be after the first occurrence of the reminder": "la date de fin doit être postérieure à la première occurrence du rappel",
+		"number of occurrences must be at least 1":                    "le nombre d'occurrences doit être d'au moins 1",
+
+		// Validation
+		"Reminders can only be delivered in a channel when they are created from a server":                                   "Les rappels ne peuvent être envoyés dans un salon que s'ils sont créés depuis un serveur",
+		"Channel <#%s> does not exist or is not visible to me":                                                               "Le salon <#%s> n'existe pas ou ne m'est pas visible",
+		"Channel <#%s> must be in this server":                                                                               "Le salon <#%s> doit faire partie de ce serveur",
+		"You are not allowed to send messages in <#%s>":                                                                      "Vous n'êtes pas autorisé à envoyer des messages dans <#%s>",
+		"I am not allowed to send messages in <#%s>":                                                                         "Je ne suis pas autorisé à envoyer des messages dans <#%s>",
+		"Invalid message link. You can get the link of a message by right-clicking on it and selecting `Copy Message Link`.": "Lien de message invalide. Vous pouvez obtenir le lien d'un message en faisant un clic droit dessus et en sélectionnant `Copier le lien du message`.",
+		"The only direct messages I can remind you about are the ones we exchanged":                                          "Les seuls messages privés que je peux vous rappeler sont ceux que nous avons échangés",
+		"The message linked does not exist or is not visible to me":                                                          "Le message lié n'existe pas ou ne m'est pas visible",
+		"You are not allowed to see the message linked":                                                                      "Vous n'êtes pas autorisé à voir le message lié",
+		"You can only remind other users and roles from a server":                                                            "Vous ne pouvez rappeler quelque chose à d'autres utilisateurs et rôles que depuis un serveur",
+		"You are not allowed to remind other users and roles in this server":                                                 "Vous n'êtes pas autorisé à rappeler quelque chose à d'autres utilisateurs et rôles sur ce serveur",
+		"Role <@&%s> does not exist in this server":                                                                          "Le rôle <@&%s> n'existe pas sur ce serveur",
+		"You are not allowed to mention <@&%s>":                                                                              "Vous n'êtes pas autorisé à mentionner <@&%s>",
+		"<@%s> is not a member of this server":                                                                               "<@%s> n'est pas membre de ce serveur",
+		"Bots cannot be reminded":                                                                                            "Les bots ne peuvent pas recevoir de rappels",
+		"<@%s> does not accept reminders from you":                                                                           "<@%s> n'accepte pas vos rappels",
+
+		// Commands
+		"**Usage:**\n```%sRemindMe [@USER | @ROLE] TIME [every INTERVAL [until YYYY-MM-DD | N times]] [--channel #CHANNEL] [MESSAGE_LINK] NOTE```**Where:**\n- `@USER` or `@ROLE` is an optional user or role to remind instead of you, if you are allowed to remind others\n- `TIME` is either a duration with a format similar to the following: `30m`, `2h`, `6h30m`, `7d12h30m`, `2 hours` or `1 day 3 hours`, or an absolute time like `at 15:30`, `tomorrow at 8am`, `friday 9am` or `2030-11-03 09:00`\n- `INTERVAL` is an optional interval at which the reminder should repeat (e.g. `24h`, `7d`, `1 month`), optionally followed by an end date or a number of occurrences\n- `#CHANNEL` is an optional channel in which the reminder should be delivered instead of being sent to you by direct message. Use `%sRemindHere` to deliver the reminder in the current channel\n- `MESSAGE_LINK` is an optional link to the message to be reminded about. If you use the command in reply to a message, you are reminded about that message instead of your command\n- `NOTE` is an optional note to attach to the reminder with less than %d characters\n\n:information_source: _You can also create a reminder by reacting to a message with any of %s, and you can view your reminders by using `%slist`. Absolute times are interpreted in the timezone set with `%stimezone`._": "**Utilisation :**\n```%sRemindMe [@UTILISATEUR | @RÔLE] TEMPS [every INTERVALLE [until AAAA-MM-JJ | N times]] [--channel #SALON] [LIEN_DU_MESSAGE] NOTE```**Où :**\n- `@UTILISATEUR` ou `@RÔLE` est un utilisateur ou un rôle facultatif à qui envoyer le rappel à votre place, si vous êtes autorisé à rappeler quelque chose à d'autres\n- `TEMPS` est soit une durée dans un format semblable aux suivants : `30m`, `2h`, `6h30m`, `7j12h30m`, `2 heures` ou `1 jour 3 heures`, soit une heure absolue comme `at 15:30`, `tomorrow at 8am`, `friday 9am` ou `2030-11-03 09:00`\n- `INTERVALLE` est un intervalle facultatif auquel le rappel doit se répéter (p. ex. `24h`, `7j`, `1 mois`), éventuellement suivi d'une date de fin ou d'un nombre d'occurrences\n- `#SALON` est un salon facultatif dans lequel le rappel doit être envoyé au lieu de vous être envoyé par message privé. Utilisez `%sRemindHere` pour envoyer le rappel dans le salon actuel\n- `LIEN_DU_MESSAGE` est un lien facultatif vers le message à rappeler. Si vous utilisez la commande en réponse à un message, c'est ce message qui vous est rappelé au lieu de votre commande\n- `NOTE` est une note facultative de moins de %d caractères à joindre au rappel\n\n:information_source: _Vous pouvez aussi créer un rappel en réagissant à un message avec l'un de %s, et consulter vos rappels avec `%slist`. Les heures absolues sont interprétées dans le fuseau horaire défini avec `%stimezone`._",
+		"Your timezone is currently `%s`.\n**Usage:**\n```%sTimezone AREA/CITY```**Where:**\n- `AREA/CITY` is the name of a timezone from the [tz database](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones), e.g. `America/Montreal` or `Europe/Paris`\n\n:information_source: _Your timezone is used to interpret absolute times like `at 15:30` and to display the time at which your reminders are due._": "Votre fuseau horaire est actuellement `%s`.\n**Utilisation :**\n```%sTimezone ZONE/VILLE```**Où :**\n- `ZONE/VILLE` est le nom d'un fuseau horaire de la [base de données tz](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones), p. ex. `America/Montreal` ou `Europe/Paris`\n\n:information_source: _Votre fuseau horaire sert à interpréter les heures absolues comme `at 15:30` et à afficher l'heure à laquelle vos rappels sont prévus._",
+		"Unknown timezone `%s`\n:information_source: _Try something like `America/Montreal`, `Europe/Paris` or `UTC`. The full list of timezones is available [here](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones)._":                                                                                                                                                                                      "Fuseau horaire inconnu `%s`\n:information_source: _Essayez quelque chose comme `America/Montreal`, `Europe/Paris` ou `UTC`. La liste complète des fuseaux horaires est disponible [ici](https://en.wikipedia.org/wiki/List_of_tz_database_time_zones)._",
+		"Your timezone has been set to `%s`, where it is currently %s.": "Votre fuseau horaire est désormais `%s`, où il est actuellement %s.",
+		"Monday 15:04": "15:04",
+		"**Usage:**\n```%sBlock @USER\n%sUnblock @USER```Blocked users can't create reminders for you.":                                                                                                                       "**Utilisation :**\n```%sBlock @UTILISATEUR\n%sUnblock @UTILISATEUR```Les utilisateurs bloqués ne peuvent pas créer de rappels pour vous.",
+		"Other users can no longer create reminders for you. Use `%sOptIn` to allow them again.":                                                                                                                              "Les autres utilisateurs ne peuvent plus créer de rappels pour vous. Utilisez `%sOptIn` pour les y autoriser à nouveau.",
+		"Other users can now create reminders for you, unless you blocked them. Use `%sOptOut` to prevent them from doing so.":                                                                                                "Les autres utilisateurs peuvent désormais créer des rappels pour vous, sauf si vous les avez bloqués. Utilisez `%sOptOut` pour les en empêcher.",
+		"**Usage:**\n```%sRemindPermission @ROLE | everyone | reset```Members who can manage messages can always remind other users and roles. This command allows members with another role, or everyone, to do so as well.": "**Utilisation :**\n```%sRemindPermission @RÔLE | everyone | reset```Les membres qui peuvent gérer les messages peuvent toujours rappeler quelque chose à d'autres utilisateurs et rôles. Cette commande permet aux membres ayant un autre rôle, ou à tout le monde, de le faire également.",
+		"I am currently responding to you in %s.\n**Usage:**\n```%sLanguage LANGUAGE | reset\n%sLanguage server LANGUAGE | reset```**Where:**\n- `LANGUAGE` is one of %s\n\n:information_source: _The language of the server is used for members who did not select a language themselves, and can only be changed by members who can manage the server._": "Je vous réponds actuellement en %s.\n**Utilisation :**\n```%sLanguage LANGUE | reset\n%sLanguage server LANGUE | reset```**Où :**\n- `LANGUE` est l'une des langues suivantes : %s\n\n:information_source: _La langue du serveur est utilisée pour les membres qui n'ont pas choisi de langue eux-mêmes, et ne peut être modifiée que par les membres qui peuvent gérer le serveur._",
+		"Unknown language `%s`, expected one of %s":                       "Langue inconnue `%s`, essayez l'une des langues suivantes : %s",
+		"I will now respond to you in %s.":                                "Je vous répondrai désormais en %s.",
+		"This command can only be used in a server.":                      "Cette commande ne peut être utilisée que sur un serveur.",
+		"You must have the Manage Server permission to use this command.": "Vous devez avoir la permission Gérer le serveur pour utiliser cette commande.",
+
+		// Configuration
+		"Reminders created from this server can only be delivered by direct message": "Les rappels créés depuis ce serveur ne peuvent être envoyés que par message privé",
+		"I cannot be used in <#%s>":                                         "Je ne peux pas être utilisé dans <#%s>",
+		"I cannot be used in this channel":                                  "Je ne peux pas être utilisé dans ce salon",
+		"The prefix must have between 1 and %d characters, without spaces":  "Le préfixe doit contenir entre 1 et %d caractères, sans espaces",
+		"The maximum number of reminders per user must be between 1 and %d": "Le nombre maximal de rappels par utilisateur doit être compris entre 1 et %d",
+		"all channels": "tous les salons",
+		"not set":      "non définie",
+		"**Configuration of this server:**\n- `prefix`: `%s`\n- `reaction-duration`: %s\n- `max-reminders`: %d\n- `channels`: %s\n- `channel-delivery`: %s\n- `language`: %s": "**Configuration de ce serveur :**\n- `prefix` : `%s`\n- `reaction-duration` : %s\n- `max-reminders` : %d\n- `channels` : %s\n- `channel-delivery` : %s\n- `language` : %s",
+		"**Usage:**\n```%[1]sConfig prefix PREFIX | reset\n%[1]sConfig reaction-duration DURATION | reset\n%[1]sConfig max-reminders NUMBER | reset\n%[1]sConfig channels #CHANNEL... | reset\n%[1]sConfig channel-delivery on | off\n%[1]sConfig language LANGUAGE | reset```**Where:**\n- `prefix` is the prefix of my commands in this server\n- `reaction-duration` is in how long reminders created by reacting to a message are due\n- `max-reminders` is the maximum number of reminders a member can have\n- `channels` are the only channels in which I can be used, but `%[1]sConfig` can be used anywhere\n- `channel-delivery` is whether reminders can be delivered in a channel rather than by direct message": "**Utilisation :**\n```%[1]sConfig prefix PRÉFIXE | reset\n%[1]sConfig reaction-duration DURÉE | reset\n%[1]sConfig max-reminders NOMBRE | reset\n%[1]sConfig channels #SALON... | reset\n%[1]sConfig channel-delivery on | off\n%[1]sConfig language LANGUE | reset```**Où :**\n- `prefix` est le préfixe de mes commandes sur ce serveur\n- `reaction-duration` est le délai des rappels créés en réagissant à un message\n- `max-reminders` est le nombre maximal de rappels qu'un membre peut avoir\n- `channels` sont les seuls salons dans lesquels je peux être utilisé, mais `%[1]sConfig` peut être utilisé partout\n- `channel-delivery` indique si les rappels peuvent être envoyés dans un salon plutôt que par message privé",
+	},
+	plurals: map[string][2]string{
+		"%d year":   {"%d an", "%d ans"},
+		"%d month":  {"%d mois", "%d mois"},
+		"%d day":    {"%d jour", "%d jours"},
+		"%d hour":   {"%d heure", "%d heures"},
+		"%d minute": {"%d minute", "%d minutes"},
+		"%d second": {"%d seconde", "%d secondes"},
+	},
+}
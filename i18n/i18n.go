@@ -0,0 +1,143 @@
+// Package i18n translates the messages of the bot into the languages it supports.
+//
+// Messages are identified by their English format string, so code using this package reads like regular English
+// code and falls back to English when a message has not been translated.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Language is a language in which the bot can respond, identified by its ISO 639-1 code
+type Language string
+
+const (
+	English Language = "en"
+	French  Language = "fr"
+	German  Language = "de"
+
+	// DefaultLanguage is the language used when neither the user nor the guild selected one
+	DefaultLanguage = English
+)
+
+// Languages are the languages supported by the bot
+var Languages = []Language{English, French, German}
+
+// catalog is the translation of the messages of the bot into a language
+type catalog struct {
+	name string // Name of the language in that language
+
+	// isSingular returns whether count requires the singular form of a noun
+	isSingular func(count int) bool
+
+	messages map[string]string    // Translations of English format strings
+	plurals  map[string][2]string // Singular and plural translations of English format strings, indexed by the singular
+}
+
+var catalogs = map[Language]*catalog{
+	English: {name: "English", isSingular: func(count int) bool { return count == 1 }},
+	French:  frenchCatalog,
+	German:  germanCatalog,
+}
+
+// ParseLanguage parses a language from its code (e.g. "fr"), a locale (e.g. "fr-CA") or its name in English or in
+// that language (e.g. "French", "français")
+func ParseLanguage(s string) (Language, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if code, _, found := strings.Cut(s, "-"); found {
+		s = code
+	}
+	for _, language := range Languages {
+		if s == string(language) || s == strings.ToLower(language.Name()) || s == strings.ToLower(English.nameOf(language)) {
+			return language, true
+		}
+	}
+	return "", false
+}
+
+// Name returns the name of the language in that language, e.g. "Français"
+func (l Language) Name() string {
+	return l.catalog().name
+}
+
+// nameOf returns the name of another language in l
+func (l Language) nameOf(other Language) string {
+	switch other {
+	case French:
+		return l.Translate("French")
+	case German:
+		return l.Translate("German")
+	}
+	return l.Translate("English")
+}
+
+// Translate returns the translation of an English message, or the message itself if it has not been translated
+func (l Language) Translate(message string) string {
+	if translation, ok := l.catalog().messages[message]; ok {
+		return translation
+	}
+	return message
+}
+
+// Sprintf translates an English format string and formats it with args, translating the args that are errors created
+// with Errorf as well
+func (l Language) Sprintf(format string, args ...any) string {
+	translatedArgs := make([]any, len(args))
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			translatedArgs[i] = l.Error(err)
+		} else {
+			translatedArgs[i] = arg
+		}
+	}
+	return fmt.Sprintf(l.Translate(format), translatedArgs...)
+}
+
+// Plural formats count with the singular or plural form of an English format string, depending on the pluralization
+// rules of the language, e.g. Plural(2, "%d day", "%d days") returns "2 jours" in French
+func (l Language) Plural(count int, singular, plural string) string {
+	c := l.catalog()
+	if translation, ok := c.plurals[singular]; ok {
+		singular, plural = translation[0], translation[1]
+	}
+	if c.isSingular(count) {
+		return fmt.Sprintf(singular, count)
+	}
+	return fmt.Sprintf(plural, count)
+}
+
+// Error returns the message of err in the language. Errors created with Errorf are translated, while other errors are
+// returned as is.
+func (l Language) Error(err error) string {
+	var localizedErr *Error
+	if errors.As(err, &localizedErr) {
+		return l.Sprintf(localizedErr.format, localizedErr.args...)
+	}
+	return err.Error()
+}
+
+func (l Language) catalog() *catalog {
+	if c, ok := catalogs[l]; ok {
+		return c
+	}
+	return catalogs[DefaultLanguage]
+}
+
+// Error is an error meant to be shown to users, whose message can be translated with Language.Error
+type Error struct {
+	format string
+	args   []any
+}
+
+// Errorf creates an Error from an English format string, which is only translated once the language of the user who
+// will see it is known
+func Errorf(format string, args ...any) error {
+	return &Error{format: format, args: args}
+}
+
+// Error returns the message of the error in English
+func (e *Error) Error() string {
+	return fmt.Sprintf(e.format, e.args...)
+}
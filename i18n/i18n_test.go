@@ -0,0 +1,122 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	scenarios := []struct {
+		Input            string
+		ExpectedLanguage Language
+		ExpectedOk       bool
+	}{
+		{Input: "en", ExpectedLanguage: English, ExpectedOk: true},
+		{Input: "FR", ExpectedLanguage: French, ExpectedOk: true},
+		{Input: "fr-CA", ExpectedLanguage: French, ExpectedOk: true},
+		{Input: "français", ExpectedLanguage: French, ExpectedOk: true},
+		{Input: "German", ExpectedLanguage: German, ExpectedOk: true},
+		{Input: " Deutsch ", ExpectedLanguage: German, ExpectedOk: true},
+		{Input: "es", ExpectedLanguage: "", ExpectedOk: false},
+		{Input: "", ExpectedLanguage: "", ExpectedOk: false},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Input, func(t *testing.T) {
+			language, ok := ParseLanguage(scenario.Input)
+			if language != scenario.ExpectedLanguage || ok != scenario.ExpectedOk {
+				t.Errorf("expected %q and %v, got %q and %v", scenario.ExpectedLanguage, scenario.ExpectedOk, language, ok)
+			}
+		})
+	}
+}
+
+func TestLanguage_Plural(t *testing.T) {
+	scenarios := []struct {
+		Language       Language
+		Count          int
+		ExpectedOutput string
+	}{
+		{Language: English, Count: 0, ExpectedOutput: "0 days"},
+		{Language: English, Count: 1, ExpectedOutput: "1 day"},
+		{Language: French, Count: 0, ExpectedOutput: "0 jour"},
+		{Language: French, Count: 1, ExpectedOutput: "1 jour"},
+		{Language: French, Count: 2, ExpectedOutput: "2 jours"},
+		{Language: German, Count: 0, ExpectedOutput: "0 Tage"},
+		{Language: German, Count: 1, ExpectedOutput: "1 Tag"},
+		{Language: "es", Count: 2, ExpectedOutput: "2 days"},
+	}
+	for _, scenario := range scenarios {
+		t.Run(fmt.Sprintf("%s-%d", scenario.Language, scenario.Count), func(t *testing.T) {
+			if output := scenario.Language.Plural(scenario.Count, "%d day", "%d days"); output != scenario.ExpectedOutput {
+				t.Errorf("expected '%s', got '%s'", scenario.ExpectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestLanguage_Error(t *testing.T) {
+	err := Errorf("Reminder #%d has been deleted.", 5)
+	if err.Error() != "Reminder #5 has been deleted." {
+		t.Error("expected error to be in English, got", err.Error())
+	}
+	if message := German.Error(err); message != "Erinnerung #5 wurde gelöscht." {
+		t.Error("expected error to be translated, got", message)
+	}
+	if message := French.Error(fmt.Errorf("oops")); message != "oops" {
+		t.Error("expected errors not created with Errorf to be returned as is, got", message)
+	}
+	// Errors passed as arguments to Sprintf are translated as well
+	if message := French.Sprintf("Error: %s", Errorf("Bots cannot be reminded")); message != "Erreur : Les bots ne peuvent pas recevoir de rappels" {
+		t.Error("expected error argument to be translated, got", message)
+	}
+}
+
+var formatVerbRegex = regexp.MustCompile(`%(?:\[(\d+)\])?[a-z]`)
+
+// countArguments returns the number of arguments used by a format string
+func countArguments(format string) int {
+	count, index := 0, 0
+	for _, match := range formatVerbRegex.FindAllStringSubmatch(format, -1) {
+		if len(match[1]) > 0 {
+			_, _ = fmt.Sscan(match[1], &index)
+		} else {
+			index++
+		}
+		if index > count {
+			count = index
+		}
+	}
+	return count
+}
+
+func TestCatalogs(t *testing.T) {
+	for _, language := range Languages {
+		if language == English {
+			continue
+		}
+		t.Run(string(language), func(t *testing.T) {
+			c := catalogs[language]
+			for english, translation := range c.messages {
+				if countArguments(english) != countArguments(translation) {
+					t.Errorf("translation of %q uses %d arguments instead of %d", english, countArguments(translation), countArguments(english))
+				}
+			}
+			for _, other := range Languages {
+				if other == English || other == language {
+					continue
+				}
+				for english := range catalogs[other].messages {
+					if _, ok := c.messages[english]; !ok {
+						t.Errorf("%q is translated in %s, but not in %s", english, other, language)
+					}
+				}
+				for english := range catalogs[other].plurals {
+					if _, ok := c.plurals[english]; !ok {
+						t.Errorf("plural %q is translated in %s, but not in %s", english, other, language)
+					}
+				}
+			}
+		})
+	}
+}
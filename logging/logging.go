@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// Formats supported by Setup
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type correlationIDKey struct{}
+
+// logSensitiveData is whether values wrapped in Sensitive are logged as is rather than redacted
+var logSensitiveData atomic.Bool
+
+// Setup replaces the default logger by one that writes logs of the given level (debug, info, warn or error) and above
+// to writer in the given format (FormatText or FormatJSON), and that adds the correlation ID of the context passed to
+// each log call, if any.
+// If logSensitive is false, the values wrapped in Sensitive are redacted.
+func Setup(writer io.Writer, level, format string, logSensitive bool) error {
+	var leveler slog.Level
+	if err := leveler.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level '%s', expected debug, info, warn or error", level)
+	}
+	options := &slog.HandlerOptions{Level: leveler}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatText:
+		handler = slog.NewTextHandler(writer, options)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(writer, options)
+	default:
+		return fmt.Errorf("invalid log format '%s', expected %s or %s", format, FormatText, FormatJSON)
+	}
+	logSensitiveData.Store(logSensitive)
+	slog.SetDefault(slog.New(&correlationHandler{Handler: handler}))
+	return nil
+}
+
+// NewCorrelationID returns a random ID used to correlate the logs of a single event
+func NewCorrelationID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// WithCorrelationID returns a copy of ctx with a new correlation ID, which is added to every log made with it
+func WithCorrelationID(ctx context.Context) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, NewCorrelationID())
+}
+
+// CorrelationID returns the correlation ID of ctx, or an empty string if it doesn't have one
+func CorrelationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// Sensitive is a value that may contain private data, such as the note of a reminder, and which is redacted from logs
+// unless logging sensitive data was enabled through Setup
+type Sensitive string
+
+// LogValue implements slog.LogValuer
+func (s Sensitive) LogValue() slog.Value {
+	if logSensitiveData.Load() || len(s) == 0 {
+		return slog.StringValue(string(s))
+	}
+	return slog.StringValue(fmt.Sprintf("[redacted %d characters]", utf8.RuneCountInString(string(s))))
+}
+
+// correlationHandler adds the correlation ID of the context of each record to it
+type correlationHandler struct {
+	slog.Handler
+}
+
+func (h *correlationHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := CorrelationID(ctx); len(id) > 0 {
+		record.AddAttrs(slog.String("correlation_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *correlationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &correlationHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *correlationHandler) WithGroup(name string) slog.Handler {
+	return &correlationHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSetup(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+	scenarios := []struct {
+		Name          string
+		Level         string
+		Format        string
+		ExpectedError bool
+	}{
+		{
+			Name:   "info-text",
+			Level:  "info",
+			Format: FormatText,
+		},
+		{
+			Name:   "debug-json",
+			Level:  "DEBUG",
+			Format: "JSON",
+		},
+		{
+			Name:          "invalid-level",
+			Level:         "verbose",
+			Format:        FormatText,
+			ExpectedError: true,
+		},
+		{
+			Name:          "invalid-format",
+			Level:         "info",
+			Format:        "xml",
+			ExpectedError: true,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			err := Setup(&bytes.Buffer{}, scenario.Level, scenario.Format, false)
+			if scenario.ExpectedError && err == nil {
+				t.Error("expected an error, got none")
+			}
+			if !scenario.ExpectedError && err != nil {
+				t.Error("expected no error, got", err.Error())
+			}
+		})
+	}
+}
+
+func TestSetupWithLevel(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+	buffer := &bytes.Buffer{}
+	if err := Setup(buffer, "warn", FormatText, false); err != nil {
+		t.Fatal("expected no error, got", err.Error())
+	}
+	slog.Info("ignored")
+	slog.Warn("written")
+	if strings.Contains(buffer.String(), "ignored") {
+		t.Error("expected logs below the configured level to be ignored, got", buffer.String())
+	}
+	if !strings.Contains(buffer.String(), "written") {
+		t.Error("expected logs at the configured level to be written, got", buffer.String())
+	}
+}
+
+func TestCorrelationID(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+	buffer := &bytes.Buffer{}
+	if err := Setup(buffer, "info", FormatJSON, false); err != nil {
+		t.Fatal("expected no error, got", err.Error())
+	}
+	if id := CorrelationID(context.Background()); len(id) != 0 {
+		t.Error("expected context without correlation ID to have an empty correlation ID, got", id)
+	}
+	ctx := WithCorrelationID(context.Background())
+	if CorrelationID(ctx) == CorrelationID(WithCorrelationID(context.Background())) {
+		t.Error("expected each context to have a different correlation ID")
+	}
+	slog.InfoContext(ctx, "test")
+	var record map[string]any
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatal("expected a JSON log, got", buffer.String())
+	}
+	if record["correlation_id"] != CorrelationID(ctx) {
+		t.Errorf("expected correlation_id to be %s, got %v", CorrelationID(ctx), record["correlation_id"])
+	}
+}
+
+func TestSensitive(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+	scenarios := []struct {
+		Name         string
+		LogSensitive bool
+		Value        Sensitive
+		Expected     string
+	}{
+		{
+			Name:         "redacted",
+			LogSensitive: false,
+			Value:        "call mom",
+			Expected:     "[redacted 8 characters]",
+		},
+		{
+			Name:         "redacted-multibyte",
+			LogSensitive: false,
+			Value:        "appeler maman à 18h",
+			Expected:     "[redacted 19 characters]",
+		},
+		{
+			Name:         "empty",
+			LogSensitive: false,
+			Value:        "",
+			Expected:     "",
+		},
+		{
+			Name:         "not-redacted",
+			LogSensitive: true,
+			Value:        "call mom",
+			Expected:     "call mom",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			if err := Setup(buffer, "info", FormatJSON, scenario.LogSensitive); err != nil {
+				t.Fatal("expected no error, got", err.Error())
+			}
+			slog.Info("test", "note", scenario.Value)
+			var record map[string]any
+			if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+				t.Fatal("expected a JSON log, got", buffer.String())
+			}
+			if record["note"] != scenario.Expected {
+				t.Errorf("expected note to be %q, got %v", scenario.Expected, record["note"])
+			}
+		})
+	}
+}
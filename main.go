@@ -1,43 +1,95 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // Embed the timezone database, since the Docker image is built from scratch
 
 	"github.com/TwiN/discord-reminder-bot/config"
 	"github.com/TwiN/discord-reminder-bot/database"
 	"github.com/TwiN/discord-reminder-bot/discord"
+	"github.com/TwiN/discord-reminder-bot/health"
+	"github.com/TwiN/discord-reminder-bot/logging"
+	"github.com/TwiN/discord-reminder-bot/metrics"
 	"github.com/bwmarrin/discordgo"
 )
 
-var (
-	killChannel chan os.Signal
-	cfg         *config.Config
-)
+// ShutdownTimeout is how long to wait for the reminders being delivered to be delivered when the bot is terminated
+const ShutdownTimeout = 20 * time.Second
+
+var cfg *config.Config
 
 func main() {
-	err := database.Initialize("sqlite", "data.db")
+	cfg = config.Get()
+	if err := logging.Setup(os.Stderr, cfg.LogLevel, cfg.LogFormat, cfg.LogNotes); err != nil {
+		panic(err)
+	}
+	store, err := database.Open(cfg.DatabaseDriver, cfg.DatabaseURL)
 	if err != nil {
 		panic(err)
 	}
-	cfg = config.Get()
+	// The health endpoints ping the database, so they must only be served once it has been opened
+	startHTTPServers(store)
 	bot, err := Connect(cfg.DiscordToken)
 	if err != nil {
 		panic(err)
 	}
-	defer bot.Close()
-	log.Printf("Bot with id=%s has connected successfully", bot.State.User.ID)
-	discord.Start(bot, cfg)
-	waitUntilTermination()
-	log.Println("Terminating bot")
+	slog.Info("Bot has connected successfully", "bot_id", bot.State.User.ID)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	stopped := discord.Start(ctx, bot, cfg, store)
+	<-ctx.Done()
+	// Restore the default behavior of the signals, so that the bot can be forced to exit by sending another one
+	stop()
+	slog.Info("Terminating bot")
+	select {
+	case <-stopped:
+	case <-time.After(ShutdownTimeout):
+		// Reminders that were sent but not deleted won't be sent again on restart
+		slog.Warn("Reminders were still being delivered after the shutdown timeout, terminating anyway", "timeout", ShutdownTimeout.String())
+	}
+	if err = bot.Close(); err != nil {
+		slog.Error("Failed to close Discord session", "error", err)
+	}
+	if err = store.Close(); err != nil {
+		slog.Error("Failed to close database", "error", err)
+	}
 }
 
-func waitUntilTermination() {
-	killChannel = make(chan os.Signal, 1)
-	signal.Notify(killChannel, syscall.SIGTERM)
-	<-killChannel
+// startHTTPServers exposes the metrics and health endpoints that are enabled in the background, sharing a server
+// between the endpoints that are configured with the same address
+func startHTTPServers(store *database.SQLStore) {
+	muxes := make(map[string]*http.ServeMux)
+	handle := func(address, pattern string, handler http.Handler) {
+		if len(address) == 0 {
+			return
+		}
+		if muxes[address] == nil {
+			muxes[address] = http.NewServeMux()
+		}
+		muxes[address].Handle(pattern, handler)
+	}
+	handle(cfg.MetricsAddress, "/metrics", metrics.Handler())
+	checker := &health.Checker{
+		Status: func() health.Status { return health.Status(discord.GetStatus()) },
+		// Reminders are loaded at least every SchedulerRefreshInterval, unless the worker is stuck
+		MaximumTimeSincePoll: 2 * discord.SchedulerRefreshInterval,
+		Ping:                 store.Ping,
+	}
+	handle(cfg.HealthAddress, "/healthz", checker.LivenessHandler())
+	handle(cfg.HealthAddress, "/readyz", checker.ReadinessHandler())
+	for address, mux := range muxes {
+		go func(address string, mux *http.ServeMux) {
+			slog.Info("Listening for HTTP requests", "address", address)
+			if err := http.ListenAndServe(address, mux); err != nil {
+				slog.Error("Failed to listen for HTTP requests", "address", address, "error", err)
+			}
+		}(address, mux)
+	}
 }
 
 // Connect starts a Discord session
@@ -46,7 +98,7 @@ func Connect(discordToken string) (*discordgo.Session, error) {
 	if err != nil {
 		return nil, err
 	}
-	session.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsGuildMessageReactions | discordgo.IntentsDirectMessages | discordgo.IntentsDirectMessageReactions
+	session.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsGuildMessageReactions | discordgo.IntentsDirectMessages | discordgo.IntentsDirectMessageReactions | discordgo.IntentMessageContent
 	err = session.Open()
 	return session, err
 }
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "discord_reminder_bot"
+
+// Sources from which reminders may be created
+const (
+	SourceCommand      = "command"
+	SourceSlashCommand = "slash_command"
+	SourceReaction     = "reaction"
+	SourceSnooze       = "snooze"
+)
+
+var (
+	// RemindersCreated is the number of reminders created, by source
+	RemindersCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reminders_created_total",
+		Help:      "Number of reminders created",
+	}, []string{"source"})
+
+	// RemindersDelivered is the number of reminders delivered, counting every occurrence of repeating reminders
+	RemindersDelivered = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reminders_delivered_total",
+		Help:      "Number of reminders delivered",
+	})
+
+	// RemindersFailed is the number of reminders that were given up on, because they could not be delivered
+	RemindersFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reminders_failed_total",
+		Help:      "Number of reminders that could not be delivered and were moved to the failed reminders",
+	})
+
+	// DeliveryAttemptsFailed is the number of delivery attempts that failed, by whether the error was transient or
+	// permanent
+	DeliveryAttemptsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "delivery_attempts_failed_total",
+		Help:      "Number of failed attempts to deliver a reminder",
+	}, []string{"error"})
+
+	// RemindersDeleted is the number of reminders deleted by users before being delivered
+	RemindersDeleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reminders_deleted_total",
+		Help:      "Number of reminders deleted by users",
+	})
+
+	// DeliveryLag is the time elapsed between when reminders were due and when they were delivered
+	DeliveryLag = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "delivery_lag_seconds",
+		Help:      "Time elapsed between when a reminder was due and when it was delivered",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900, 3600},
+	})
+
+	// QueuedReminders is the number of reminders waiting to be delivered
+	QueuedReminders = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queued_reminders",
+		Help:      "Number of reminders waiting to be delivered",
+	})
+
+	// DatabaseQueryDuration is the time taken by database queries, by query
+	DatabaseQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "database_query_duration_seconds",
+		Help:      "Time taken by database queries",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"query"})
+
+	// DiscordRESTErrors is the number of errors returned by Discord's API, by endpoint and HTTP status code
+	DiscordRESTErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "discord_rest_errors_total",
+		Help:      "Number of errors returned by Discord's REST API",
+	}, []string{"endpoint", "status"})
+)
+
+// ObserveDatabaseQuery records the time taken by a database query that started at start
+func ObserveDatabaseQuery(query string, start time.Time) {
+	DatabaseQueryDuration.WithLabelValues(query).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDiscordRESTError records err, if it isn't nil, as an error returned by the given endpoint of Discord's API.
+// The status is the HTTP status code of the response, or "error" if no response was received.
+// err is returned as is, so that calls to the API can be wrapped.
+func ObserveDiscordRESTError(endpoint string, err error) error {
+	if err == nil {
+		return nil
+	}
+	status := "error"
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		status = strconv.Itoa(restErr.Response.StatusCode)
+	}
+	DiscordRESTErrors.WithLabelValues(endpoint, status).Inc()
+	return err
+}
+
+// Handler returns the handler exposing the metrics in the Prometheus format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveDiscordRESTError(t *testing.T) {
+	scenarios := []struct {
+		Name           string
+		Err            error
+		ExpectedStatus string
+	}{
+		{
+			Name:           "rest-error",
+			Err:            &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}},
+			ExpectedStatus: "403",
+		},
+		{
+			Name:           "network-error",
+			Err:            errors.New("connection reset by peer"),
+			ExpectedStatus: "error",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			counter := DiscordRESTErrors.WithLabelValues(scenario.Name, scenario.ExpectedStatus)
+			before := testutil.ToFloat64(counter)
+			if err := ObserveDiscordRESTError(scenario.Name, scenario.Err); err != scenario.Err {
+				t.Error("expected error to be returned as is, got", err)
+			}
+			if after := testutil.ToFloat64(counter); after != before+1 {
+				t.Errorf("expected counter to be incremented from %v, got %v", before, after)
+			}
+		})
+	}
+	if err := ObserveDiscordRESTError("nil", nil); err != nil {
+		t.Error("expected nil, got", err)
+	}
+	if count := testutil.CollectAndCount(DiscordRESTErrors, "discord_reminder_bot_discord_rest_errors_total"); count != len(scenarios) {
+		t.Errorf("expected %d series, got %d", len(scenarios), count)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	RemindersCreated.WithLabelValues(SourceReaction).Inc()
+	ObserveDatabaseQuery("CountReminders", time.Now())
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	for _, expected := range []string{
+		`discord_reminder_bot_reminders_created_total{source="reaction"} 1`,
+		`discord_reminder_bot_database_query_duration_seconds_count{query="CountReminders"} 1`,
+		"discord_reminder_bot_delivery_lag_seconds_count 0",
+		"discord_reminder_bot_queued_reminders 0",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected metrics to contain %q", expected)
+		}
+	}
+}